@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"super-fantasy-api/db"
+)
+
+// runCommand executes a CLI command, e.g.
+//
+//	./super-fantasy-api import-crosswalk SFBB-Player-ID-Map.csv
+func runCommand(args []string) {
+	switch args[0] {
+	case "import-crosswalk":
+		if len(args) < 2 {
+			log.Fatal("Usage: super-fantasy-api import-crosswalk <file.csv>")
+		}
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			log.Fatal("Failed to read crosswalk file:", err)
+		}
+
+		report, err := db.ImportCrosswalk(string(data))
+		if err != nil {
+			log.Fatal("Failed to import crosswalk:", err)
+		}
+		log.Printf("Imported %d rows: %d matched, %d not in the registry, %d skipped, %d unmatched",
+			report.Rows, report.Matched, report.NotFound, report.Skipped, len(report.Unmatched))
+		for _, row := range report.Unmatched {
+			log.Printf("  row %d: %s (%s) matches %d players", row.Row, row.Name, row.Team, len(row.Candidates))
+		}
+	default:
+		log.Fatalf("Unknown command %q", args[0])
+	}
+}
@@ -0,0 +1,189 @@
+package baseball
+
+import (
+	"sort"
+	"strings"
+
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+)
+
+// ADPFormat adapts one site's average draft position export. Each format decodes into its own
+// csv-tagged struct, which converts to the stored entry.
+type ADPFormat struct {
+	Name   string // stored source name, e.g. "nfbc"
+	Label  string // display name, e.g. "NFBC"
+	newRow func() ADPRow
+}
+
+// ADPRow is one decoded row of an ADP export
+type ADPRow interface {
+	Entry() models.ADPEntry
+}
+
+// adpFormats are tried in order when detecting a header's format
+var adpFormats = []ADPFormat{
+	{Name: "nfbc", Label: "NFBC", newRow: func() ADPRow { return &NFBCADP{} }},
+	{Name: "fantasypros", Label: "FantasyPros", newRow: func() ADPRow { return &FantasyProsADP{} }},
+	{Name: "yahoo", Label: "Yahoo", newRow: func() ADPRow { return &YahooADP{} }},
+}
+
+// ADPFormats lists the supported ADP formats
+func ADPFormats() []ADPFormat {
+	return adpFormats
+}
+
+// LookupADPFormat finds an ADP format by its stored name
+func LookupADPFormat(name string) (ADPFormat, bool) {
+	for _, format := range adpFormats {
+		if format.Name == name {
+			return format, true
+		}
+	}
+	return ADPFormat{}, false
+}
+
+// ADPFormatNames lists the ADP format names, for error messages
+func ADPFormatNames() []string {
+	names := make([]string, len(adpFormats))
+	for i, format := range adpFormats {
+		names[i] = format.Name
+	}
+	return names
+}
+
+// DetectADPFormat finds the first ADP format whose required columns a CSV header carries
+func DetectADPFormat(header []string) (ADPFormat, bool) {
+	for _, format := range adpFormats {
+		if _, err := utils.MapColumns(header, format.NewRow()); err == nil {
+			return format, true
+		}
+	}
+	return ADPFormat{}, false
+}
+
+// NewRow returns an empty row to decode one CSV record into
+func (f ADPFormat) NewRow() ADPRow {
+	return f.newRow()
+}
+
+// NFBCADP is a row of the NFBC ADP export, whose names are "Last, First"
+// Based on CSV: "Rank","Player","Team","Position(s)","ADP","Min Pick","Max Pick","Difference","# Picks"
+type NFBCADP struct {
+	Rank      int     `csv:"Rank,optional"`
+	Player    string  `csv:"Player"`
+	Team      string  `csv:"Team,optional"`
+	Positions string  `csv:"Position(s)|Positions|Position,optional"`
+	ADP       float64 `csv:"ADP"`
+	MinPick   float64 `csv:"Min Pick"`
+	MaxPick   float64 `csv:"Max Pick"`
+}
+
+// FantasyProsADP is a row of the FantasyPros consensus ADP export; AVG is the consensus
+// Based on CSV: "Rank","Player","Team","Positions","ESPN","CBS","RTS","NFBC","FT","AVG"
+type FantasyProsADP struct {
+	Rank      int     `csv:"Rank"`
+	Player    string  `csv:"Player|Player Name"`
+	Team      string  `csv:"Team,optional"`
+	Positions string  `csv:"Positions|POS,optional"`
+	ADP       float64 `csv:"AVG"`
+}
+
+// YahooADP is a row of Yahoo's draft analysis export
+// Based on CSV: "Rank","Player","Team","Pos","Avg Pick","Avg Round","% Drafted"
+type YahooADP struct {
+	Rank      int     `csv:"Rank,optional"`
+	Player    string  `csv:"Player|Name"`
+	Team      string  `csv:"Team,optional"`
+	Positions string  `csv:"Pos|Position|Positions,optional"`
+	ADP       float64 `csv:"Avg Pick"`
+}
+
+func (r NFBCADP) Entry() models.ADPEntry {
+	name := r.Player
+	if last, first, ok := strings.Cut(name, ","); ok {
+		name = strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+	}
+	return adpEntry(r.Rank, name, r.Team, r.Positions, r.ADP, r.MinPick, r.MaxPick)
+}
+
+func (r FantasyProsADP) Entry() models.ADPEntry {
+	return adpEntry(r.Rank, r.Player, r.Team, r.Positions, r.ADP, 0, 0)
+}
+
+func (r YahooADP) Entry() models.ADPEntry {
+	return adpEntry(r.Rank, r.Player, r.Team, r.Positions, r.ADP, 0, 0)
+}
+
+// adpEntry builds an entry from a row's cells. Some exports fold the team and positions into
+// the player cell ("Aaron Judge (NYY - RF,DH)"); they're split out when the row has no team.
+func adpEntry(rank int, player, team, positions string, adp, minPick, maxPick float64) models.ADPEntry {
+	if open := strings.LastIndex(player, " ("); open > 0 && strings.HasSuffix(player, ")") && team == "" {
+		detail := player[open+2 : len(player)-1]
+		player = player[:open]
+		team, positions, _ = strings.Cut(detail, " - ")
+		if _, known := models.LookupTeam(team); positions == "" && !known {
+			team, positions = "", detail // "(RF,DH)"
+		}
+	}
+	return models.ADPEntry{
+		Name:      utils.NormalizeName(strings.TrimSpace(player)),
+		Team:      models.CanonicalTeam(strings.TrimSpace(team)),
+		Positions: strings.TrimSpace(positions),
+		Rank:      rank,
+		ADP:       adp,
+		MinPick:   minPick,
+		MaxPick:   maxPick,
+	}
+}
+
+// ADPRole is the role an entry's positions point to: "pitcher" when they're all pitching
+// positions, "batter" when none are, and "" when they're mixed or missing
+func ADPRole(positions string) string {
+	var pitching, batting bool
+	for _, position := range strings.FieldsFunc(strings.ToUpper(positions), func(r rune) bool {
+		return r == ',' || r == '/' || r == ' ' || r == ';'
+	}) {
+		switch position {
+		case "P", "SP", "RP":
+			pitching = true
+		default:
+			batting = true
+		}
+	}
+	switch {
+	case pitching && !batting:
+		return "pitcher"
+	case batting && !pitching:
+		return "batter"
+	}
+	return ""
+}
+
+// ADPRanks are the players' ranks by value and by ADP, counting from 1
+type ADPRanks struct {
+	Value int
+	ADP   int
+}
+
+// RankADP ranks players by value (highest first) and by ADP (earliest first); values[i] and
+// adps[i] are the same player's. Ties keep the players' order, so callers pass them in a fixed order.
+func RankADP(values, adps []float64) []ADPRanks {
+	ranks := make([]ADPRanks, len(values))
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return values[order[i]] > values[order[j]] })
+	for rank, i := range order {
+		ranks[i].Value = rank + 1
+	}
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return adps[order[i]] < adps[order[j]] })
+	for rank, i := range order {
+		ranks[i].ADP = rank + 1
+	}
+	return ranks
+}
@@ -0,0 +1,122 @@
+package baseball
+
+import (
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+)
+
+func TestADPFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		export string
+		want   []models.ADPEntry
+	}{
+		{
+			// NFBC names are "Last, First"
+			format: "nfbc",
+			export: "Rank,Player,Team,Position(s),ADP,Min Pick,Max Pick,Difference,# Picks\n" +
+				"1,\"Judge, Aaron\",NYY,OF,1.4,1,3,0,100\n" +
+				"3,\"Skenes, Paul\",PIT,P,9.5,5,14,0,100\n",
+			want: []models.ADPEntry{
+				{Name: "Aaron Judge", Team: "NYY", Positions: "OF", Rank: 1, ADP: 1.4, MinPick: 1, MaxPick: 3},
+				{Name: "Paul Skenes", Team: "PIT", Positions: "P", Rank: 3, ADP: 9.5, MinPick: 5, MaxPick: 14},
+			},
+		},
+		{
+			// FantasyPros sometimes folds the team and positions into the player cell
+			format: "fantasypros",
+			export: "\"Rank\",\"Player\",\"Team\",\"Positions\",\"ESPN\",\"CBS\",\"NFBC\",\"AVG\"\n" +
+				"\"1\",\"Aaron Judge\",\"NYY\",\"RF,DH\",\"1\",\"2\",\"1\",\"1.3\"\n" +
+				"\"2\",\"Bobby Witt Jr. (KCR - SS)\",\"\",\"\",\"2\",\"1\",\"3\",\"2.0\"\n" +
+				"\"3\",\"Shohei Ohtani (SP,DH)\",\"\",\"\",\"3\",\"3\",\"2\",\"2.7\"\n",
+			want: []models.ADPEntry{
+				{Name: "Aaron Judge", Team: "NYY", Positions: "RF,DH", Rank: 1, ADP: 1.3},
+				{Name: "Bobby Witt Jr", Team: "KC", Positions: "SS", Rank: 2, ADP: 2},
+				{Name: "Shohei Ohtani", Positions: "SP,DH", Rank: 3, ADP: 2.7},
+			},
+		},
+		{
+			// Undrafted players have no average pick
+			format: "yahoo",
+			export: "Rank,Player,Team,Pos,Avg Pick,Avg Round,% Drafted\n" +
+				"1,José Ramírez,CLE,3B,4.8,1.0,100%\n" +
+				"2,Some Prospect,SEA,SS,-,-,2%\n",
+			want: []models.ADPEntry{
+				{Name: "Jose Ramirez", Team: "CLE", Positions: "3B", Rank: 1, ADP: 4.8},
+				{Name: "Some Prospect", Team: "SEA", Positions: "SS", Rank: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			records, err := csv.NewReader(strings.NewReader(tt.export)).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if source, _, ok := DetectSource(records[0]); ok {
+				t.Fatalf("header detected as %s projections", source.Name())
+			}
+			format, ok := DetectADPFormat(records[0])
+			if !ok || format.Name != tt.format {
+				t.Fatalf("DetectADPFormat = %q, %v; want %s", format.Name, ok, tt.format)
+			}
+			columns, err := utils.MapColumns(records[0], format.NewRow())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []models.ADPEntry
+			for _, record := range records[1:] {
+				row := format.NewRow()
+				columns.Decode(record, row)
+				got = append(got, row.Entry())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("entries = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := DetectADPFormat([]string{"Player", "Team", "HR"}); ok {
+		t.Error("a header without an ADP column was detected")
+	}
+	if _, ok := LookupADPFormat("espn"); ok {
+		t.Error("LookupADPFormat found an unsupported format")
+	}
+}
+
+func TestADPRole(t *testing.T) {
+	tests := []struct {
+		positions string
+		want      string
+	}{
+		{"OF", "batter"},
+		{"1B/DH", "batter"},
+		{"UT", "batter"},
+		{"SP,RP", "pitcher"},
+		{"p", "pitcher"},
+		{"SP,DH", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ADPRole(tt.positions); got != tt.want {
+			t.Errorf("ADPRole(%q) = %q, want %q", tt.positions, got, tt.want)
+		}
+	}
+}
+
+func TestRankADP(t *testing.T) {
+	got := RankADP([]float64{500, 400, 300, 200}, []float64{3, 1, 40, 2})
+	want := []ADPRanks{{Value: 1, ADP: 3}, {Value: 2, ADP: 1}, {Value: 3, ADP: 4}, {Value: 4, ADP: 2}}
+	if !slices.Equal(got, want) {
+		t.Errorf("RankADP = %v, want %v", got, want)
+	}
+	// Ties keep the players' order
+	if got, want := RankADP([]float64{10, 10}, []float64{5, 5}), []ADPRanks{{1, 1}, {2, 2}}; !slices.Equal(got, want) {
+		t.Errorf("RankADP with ties = %v, want %v", got, want)
+	}
+}
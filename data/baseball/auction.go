@@ -0,0 +1,123 @@
+package baseball
+
+import (
+	"fmt"
+	"sort"
+
+	"super-fantasy-api/models"
+)
+
+// Auction defaults
+const (
+	defaultBudget = 260
+	defaultMinBid = 1
+)
+
+// Auction prices players for a league's auction draft. NewAuction checks the league's terms;
+// Value then prices a pool of players from their projected values.
+type Auction struct {
+	settings models.AuctionSettings
+	roster   *Roster
+}
+
+// AuctionValue is one player's price
+type AuctionValue struct {
+	Slot             string  // the starting slot the player fills, "" when they don't start
+	Replacement      float64 // replacement level at the player's scarcest eligible slot
+	AboveReplacement float64
+	Dollars          float64
+	Rostered         bool
+}
+
+// NewAuction validates the league's terms, filling in defaults for what's left zero
+func NewAuction(settings models.AuctionSettings) (*Auction, error) {
+	if settings.Teams < 0 || settings.Budget < 0 || settings.MinBid < 0 {
+		return nil, fmt.Errorf("teams, budget and min_bid can't be negative")
+	}
+	if settings.Teams == 0 {
+		settings.Teams = defaultTeams
+	}
+	if settings.Budget == 0 {
+		settings.Budget = defaultBudget
+	}
+	if settings.MinBid == 0 {
+		settings.MinBid = defaultMinBid
+	}
+	if len(settings.Roster) == 0 {
+		settings.Roster = models.DefaultRoster
+	}
+
+	roster, err := NewRoster(settings.Teams, settings.Roster)
+	if err != nil {
+		return nil, err
+	}
+	spots := (roster.Starters() + roster.Bench()) / settings.Teams
+	if settings.MinBid*float64(spots) > settings.Budget {
+		return nil, fmt.Errorf("a $%g budget can't fill %d roster spots at the $%g minimum bid", settings.Budget, spots, settings.MinBid)
+	}
+	return &Auction{settings: settings, roster: roster}, nil
+}
+
+// Settings returns the terms with defaults filled in
+func (a *Auction) Settings() models.AuctionSettings {
+	return a.settings
+}
+
+// Roster is the league's roster the auction fills
+func (a *Auction) Roster() *Roster {
+	return a.roster
+}
+
+// Value prices every player, returning one price per player in the same order and each
+// starting slot's replacement level.
+//
+// The league's starting spots go to the players that make the starters' total value highest
+// (see Roster.Fill), and replacement levels come from the best players left over. Every
+// rostered player costs at least the minimum bid; the rest of the league's money is split among
+// the starters in proportion to their value above replacement, so the rostered players' prices
+// add up to the teams' combined budget. Bench spots go to the best players left at the minimum
+// bid. Players nobody rosters are priced on the same scale, which puts them at or under the
+// minimum.
+func (a *Auction) Value(players []RosterPlayer) ([]AuctionValue, map[string]float64) {
+	fits, levels := a.roster.Fill(players)
+	values := make([]AuctionValue, len(players))
+	var starters, leftover []int
+	for i, fit := range fits {
+		values[i].Slot = fit.Slot
+		values[i].Replacement = fit.Replacement
+		values[i].AboveReplacement = players[i].Value - fit.Replacement
+		if fit.Slot != "" {
+			starters = append(starters, i)
+		} else {
+			leftover = append(leftover, i)
+		}
+	}
+
+	sort.SliceStable(leftover, func(a, b int) bool {
+		return values[leftover[a]].AboveReplacement > values[leftover[b]].AboveReplacement
+	})
+	bench := leftover[:min(a.roster.Bench(), len(leftover))]
+
+	var above float64
+	for _, i := range starters {
+		above += values[i].AboveReplacement
+	}
+	rostered := len(starters) + len(bench)
+	pot := a.settings.Budget*float64(a.settings.Teams) - a.settings.MinBid*float64(rostered)
+	var perUnit float64
+	if above > 0 {
+		perUnit = pot / above
+	}
+
+	for i := range values {
+		values[i].Dollars = a.settings.MinBid + values[i].AboveReplacement*perUnit
+	}
+	for _, i := range starters {
+		values[i].Rostered = true
+	}
+	for _, i := range bench {
+		values[i].Rostered = true
+		values[i].Dollars = a.settings.MinBid
+	}
+	return values, levels
+}
@@ -0,0 +1,75 @@
+package baseball
+
+import (
+	"math"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+// A one-team league with $10 to fill an OF, a P and a bench spot: b2 sets the OF replacement
+// level at 30 and p2 the pitchers' at 10, so b1 and p1 start 20 and 30 above replacement.
+// The $7 left after the $1 minimums is split $0.14 a point.
+func TestAuctionValue(t *testing.T) {
+	auction, err := NewAuction(models.AuctionSettings{
+		Teams:  1,
+		Budget: 10,
+		Roster: []models.RosterSlot{{Position: "OF", Count: 1}, {Position: "P", Count: 1}, {Position: "BN", Count: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	players := []RosterPlayer{
+		{Position: "batter", Eligible: []string{"OF"}, Value: 50},
+		{Position: "batter", Eligible: []string{"OF"}, Value: 30},
+		{Position: "batter", Eligible: []string{"OF"}, Value: 20},
+		{Position: "pitcher", Eligible: []string{"SP"}, Value: 40},
+		{Position: "pitcher", Eligible: []string{"RP"}, Value: 10},
+	}
+	want := []AuctionValue{
+		{Slot: "OF", Replacement: 30, AboveReplacement: 20, Dollars: 3.8, Rostered: true},
+		{Replacement: 30, AboveReplacement: 0, Dollars: 1, Rostered: true}, // the bench spot
+		{Replacement: 30, AboveReplacement: -10, Dollars: -0.4},
+		{Slot: "P", Replacement: 10, AboveReplacement: 30, Dollars: 5.2, Rostered: true},
+		{Replacement: 10, AboveReplacement: 0, Dollars: 1},
+	}
+
+	values, levels := auction.Value(players)
+	if levels["OF"] != 30 || levels["P"] != 10 {
+		t.Errorf("replacement levels = %v, want OF 30 and P 10", levels)
+	}
+	var spent float64
+	for i, got := range values {
+		if got.Slot != want[i].Slot || got.Replacement != want[i].Replacement || got.AboveReplacement != want[i].AboveReplacement ||
+			math.Abs(got.Dollars-want[i].Dollars) > 1e-9 || got.Rostered != want[i].Rostered {
+			t.Errorf("player %d = %+v, want %+v", i, got, want[i])
+		}
+		if got.Rostered {
+			spent += got.Dollars
+		}
+	}
+	if math.Abs(spent-10) > 1e-9 {
+		t.Errorf("rostered players cost $%v, want the $10 budget", spent)
+	}
+}
+
+func TestNewAuction(t *testing.T) {
+	auction, err := NewAuction(models.AuctionSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings := auction.Settings(); settings.Teams != 12 || settings.Budget != 260 || settings.MinBid != 1 || len(settings.Roster) != len(models.DefaultRoster) {
+		t.Errorf("defaults = %+v", settings)
+	}
+
+	for _, settings := range []models.AuctionSettings{
+		{Teams: -1},
+		{MinBid: -1},
+		{Roster: []models.RosterSlot{{Position: "OF", Count: 0}}},
+		{Budget: 2, Roster: []models.RosterSlot{{Position: "OF", Count: 2}, {Position: "BN", Count: 1}}},
+	} {
+		if _, err := NewAuction(settings); err == nil {
+			t.Errorf("NewAuction(%+v) succeeded, want an error", settings)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package baseball
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"super-fantasy-api/models"
+)
+
+// Category valuation defaults: a 12-team league rostering 13 hitters and 9 pitchers
+const (
+	defaultTeams    = 12
+	defaultHitters  = 13
+	defaultPitchers = 9
+)
+
+// rateVolumes are the playing-time stats common rate categories are weighted by, for
+// categories that don't name one
+var rateVolumes = map[models.Stat]models.Stat{
+	models.StatAVG:  models.StatAB,
+	models.StatOBP:  models.StatPA,
+	models.StatSLG:  models.StatAB,
+	models.StatOPS:  models.StatPA,
+	models.StatWOBA: models.StatPA,
+	models.StatERA:  models.StatIP,
+	models.StatWHIP: models.StatIP,
+}
+
+// lowerIsBetter are the stats whose categories are won by the lowest total
+var lowerIsBetter = []models.Stat{models.StatERA, models.StatWHIP, models.StatL, models.StatBS}
+
+// CategoryValuer values players for a categories league. NewCategoryValuer checks the
+// categories once; Value then measures every line against the pool of players the league
+// would roster.
+type CategoryValuer struct {
+	settings   models.CategorySettings
+	categories []category
+}
+
+type category struct {
+	models.Category
+	stat   models.Stat
+	volume models.Stat // empty for counting stats
+}
+
+// CategoryValue is one player's value in each category, keyed by the category's stat, and
+// their total. Categories the player's sources don't project are left out and count as 0.
+type CategoryValue struct {
+	Values map[models.Stat]float64
+	Total  float64
+}
+
+// poolStats are the player pool's averages that values are measured against
+type poolStats struct {
+	rate   float64 // playing-time weighted rate, for rate categories
+	mean   float64 // mean contribution
+	stdDev float64
+	volume float64 // mean playing time per player, for rate categories
+}
+
+// NewCategoryValuer validates the settings, filling in 5x5 roto and the default league size
+// for what's left unset
+func NewCategoryValuer(settings models.CategorySettings) (*CategoryValuer, error) {
+	switch settings.Method {
+	case "":
+		settings.Method = models.MethodZScore
+	case models.MethodZScore, models.MethodSGP:
+	default:
+		return nil, fmt.Errorf("method must be '%s' or '%s'", models.MethodZScore, models.MethodSGP)
+	}
+	if settings.Teams < 0 || settings.Hitters < 0 || settings.Pitchers < 0 {
+		return nil, fmt.Errorf("teams, hitters and pitchers can't be negative")
+	}
+	if settings.Teams == 0 {
+		settings.Teams = defaultTeams
+	}
+	if settings.Hitters == 0 {
+		settings.Hitters = defaultHitters
+	}
+	if settings.Pitchers == 0 {
+		settings.Pitchers = defaultPitchers
+	}
+	if len(settings.Categories) == 0 {
+		settings.Categories = models.RotoCategories
+	}
+
+	valuer := &CategoryValuer{settings: settings}
+	for i, c := range settings.Categories {
+		compiled, err := compileCategory(c, settings.Method)
+		if err != nil {
+			return nil, fmt.Errorf("category %d (%s): %v", i+1, c.Stat, err)
+		}
+		valuer.categories = append(valuer.categories, compiled)
+	}
+	return valuer, nil
+}
+
+func compileCategory(c models.Category, method string) (category, error) {
+	compiled := category{Category: c}
+	if c.Position != "batter" && c.Position != "pitcher" {
+		return compiled, fmt.Errorf("position must be 'batter' or 'pitcher'")
+	}
+	stat, ok := models.LookupStat(c.Stat)
+	if !ok {
+		return compiled, fmt.Errorf("unknown stat")
+	}
+	compiled.stat = stat
+	if slices.Contains(lowerIsBetter, stat) {
+		compiled.Lower = true
+	}
+
+	switch {
+	case c.Volume != "":
+		volume, ok := models.LookupStat(c.Volume)
+		if !ok {
+			return compiled, fmt.Errorf("unknown volume stat %q", c.Volume)
+		}
+		compiled.volume = volume
+	default:
+		compiled.volume = rateVolumes[stat]
+	}
+	if method == models.MethodSGP && c.SGP <= 0 {
+		return compiled, fmt.Errorf("sgp method needs a positive sgp denominator")
+	}
+	return compiled, nil
+}
+
+// Settings returns the settings with defaults filled in
+func (v *CategoryValuer) Settings() models.CategorySettings {
+	return v.settings
+}
+
+// Categories lists the categories for a position, in settings order
+func (v *CategoryValuer) Categories(position string) []models.Stat {
+	var stats []models.Stat
+	for _, c := range v.categories {
+		if c.Position == position {
+			stats = append(stats, c.stat)
+		}
+	}
+	return stats
+}
+
+// Value values every line, returning one value per line in the same order. Batters and pitchers
+// are valued against their own pools. The pool starts as every player with playing time, and
+// is then narrowed to the league's rostered players (teams x hitters or pitchers) by a first
+// pass, so thousands of minor leaguers don't drag the averages down.
+func (v *CategoryValuer) Value(lines []Line) []CategoryValue {
+	values := make([]CategoryValue, len(lines))
+	for _, position := range []string{"batter", "pitcher"} {
+		var players []int
+		for i, line := range lines {
+			if line.Position == position {
+				players = append(players, i)
+			}
+		}
+		var pool []int
+		for _, i := range players {
+			if playingTime(lines[i]) > 0 {
+				pool = append(pool, i)
+			}
+		}
+
+		stats := v.poolStats(lines, pool, position)
+		for _, i := range players {
+			values[i] = v.value(lines[i], stats)
+		}
+
+		size := v.settings.Teams * v.settings.Hitters
+		if position == "pitcher" {
+			size = v.settings.Teams * v.settings.Pitchers
+		}
+		if len(pool) <= size {
+			continue
+		}
+		sort.SliceStable(pool, func(a, b int) bool { return values[pool[a]].Total > values[pool[b]].Total })
+		stats = v.poolStats(lines, pool[:size], position)
+		for _, i := range players {
+			values[i] = v.value(lines[i], stats)
+		}
+	}
+	return values
+}
+
+// playingTime is a line's plate appearances (or at bats) or innings
+func playingTime(line Line) float64 {
+	if line.Position == "pitcher" {
+		return line.Get(models.StatIP)
+	}
+	if line.Has(models.StatPA) {
+		return line.Get(models.StatPA)
+	}
+	return line.Get(models.StatAB)
+}
+
+// poolStats measures the pool in each of the position's categories
+func (v *CategoryValuer) poolStats(lines []Line, pool []int, position string) map[models.Stat]poolStats {
+	stats := make(map[models.Stat]poolStats)
+	for _, c := range v.categories {
+		if c.Position != position {
+			continue
+		}
+		var s poolStats
+		if c.volume != "" {
+			var weighted, volume float64
+			for _, i := range pool {
+				if lines[i].Has(c.stat) && lines[i].Has(c.volume) {
+					weighted += lines[i].Get(c.stat) * lines[i].Get(c.volume)
+					volume += lines[i].Get(c.volume)
+				}
+			}
+			if volume > 0 {
+				s.rate = weighted / volume
+			}
+			if len(pool) > 0 {
+				s.volume = volume / float64(len(pool))
+			}
+		}
+
+		var contributions []float64
+		for _, i := range pool {
+			if x, ok := c.contribution(lines[i], s); ok {
+				contributions = append(contributions, x)
+			}
+		}
+		s.mean, s.stdDev = meanStdDev(contributions)
+		stats[c.stat] = s
+	}
+	return stats
+}
+
+// contribution is what a line adds to a team's category: the stat itself for counting stats,
+// and for rates the playing time times how far the rate is from the pool's, so a rate moves
+// a team's total in proportion to how much the player plays. It's negated when lower wins.
+func (c category) contribution(line Line, pool poolStats) (float64, bool) {
+	if !line.Has(c.stat) {
+		return 0, false
+	}
+	x := line.Get(c.stat)
+	if c.volume != "" {
+		if !line.Has(c.volume) {
+			return 0, false
+		}
+		x = line.Get(c.volume) * (x - pool.rate)
+	}
+	if c.Lower {
+		x = -x
+	}
+	return x, true
+}
+
+// value scores one line in each of its position's categories
+func (v *CategoryValuer) value(line Line, pool map[models.Stat]poolStats) CategoryValue {
+	value := CategoryValue{Values: make(map[models.Stat]float64)}
+	for _, c := range v.categories {
+		if c.Position != line.Position {
+			continue
+		}
+		stats := pool[c.stat]
+		x, ok := c.contribution(line, stats)
+		if !ok {
+			continue
+		}
+
+		var points float64
+		switch {
+		case v.settings.Method == models.MethodZScore && stats.stdDev > 0:
+			points = (x - stats.mean) / stats.stdDev
+		case v.settings.Method == models.MethodSGP && c.volume == "":
+			points = x / c.SGP
+		case v.settings.Method == models.MethodSGP:
+			// The change in an average team's rate from rostering the player
+			lineup := v.settings.Hitters
+			if c.Position == "pitcher" {
+				lineup = v.settings.Pitchers
+			}
+			if team := stats.volume * float64(lineup); team > 0 {
+				points = x / team / c.SGP
+			}
+		}
+		value.Values[c.stat] = points
+		value.Total += points
+	}
+	return value
+}
+
+func meanStdDev(values []float64) (mean, stdDev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, x := range values {
+		mean += x
+	}
+	mean /= float64(len(values))
+	for _, x := range values {
+		stdDev += (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(stdDev / float64(len(values)))
+}
@@ -0,0 +1,123 @@
+package baseball
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+// categoryLines are a pool small enough to value by hand. With two hitters and two pitchers
+// rostered per team, the pool is narrowed to A and B; both pitchers stay in it.
+func categoryLines() []Line {
+	batter := func(name string, ab, hr, avg float64) Line {
+		return Line{Name: name, Position: "batter", Stats: map[models.Stat]float64{models.StatAB: ab, models.StatHR: hr, models.StatAVG: avg}}
+	}
+	pitcher := func(name string, ip, era float64) Line {
+		return Line{Name: name, Position: "pitcher", Stats: map[models.Stat]float64{models.StatIP: ip, models.StatERA: era}}
+	}
+	return []Line{
+		batter("A", 500, 40, .300),
+		pitcher("P1", 200, 3),
+		batter("B", 500, 20, .250),
+		batter("C", 100, 0, .200),
+		pitcher("P2", 100, 4.5),
+		// No playing time projected: valued, but not part of the pool
+		{Name: "D", Position: "batter", Stats: map[models.Stat]float64{models.StatHR: 5}},
+	}
+}
+
+func TestCategoryValuer(t *testing.T) {
+	categories := []models.Category{
+		{Position: "batter", Stat: "HR", SGP: 10},
+		{Position: "batter", Stat: "AVG", SGP: 0.01},
+		{Position: "pitcher", Stat: "ERA", SGP: 0.5}, // lower is better without saying so
+	}
+
+	// The pool hits .275 with 30 homers (SD 10); A's 500 at bats 25 points above it are 12.5
+	// hits, one SD. The pitchers' pool ERA is 3.50: P1's 200 innings half a run under it
+	// contribute 100, P2's 100 innings a run over it -100, one SD each way.
+	tests := []struct {
+		method string
+		want   map[string]map[models.Stat]float64
+	}{
+		{
+			method: models.MethodZScore,
+			want: map[string]map[models.Stat]float64{
+				"A":  {models.StatHR: 1, models.StatAVG: 1},
+				"B":  {models.StatHR: -1, models.StatAVG: -1},
+				"C":  {models.StatHR: -3, models.StatAVG: -0.6},
+				"D":  {models.StatHR: -2.5},
+				"P1": {models.StatERA: 1},
+				"P2": {models.StatERA: -1},
+			},
+		},
+		{
+			// Counting stats over the denominator; rates by how far they move an average
+			// team (two players of 500 at bats, or of 150 innings)
+			method: models.MethodSGP,
+			want: map[string]map[models.Stat]float64{
+				"A":  {models.StatHR: 4, models.StatAVG: 1.25},
+				"B":  {models.StatHR: 2, models.StatAVG: -1.25},
+				"C":  {models.StatHR: 0, models.StatAVG: -0.75},
+				"D":  {models.StatHR: 0.5},
+				"P1": {models.StatERA: 100.0 / 300 / 0.5},
+				"P2": {models.StatERA: -100.0 / 300 / 0.5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			valuer, err := NewCategoryValuer(models.CategorySettings{Method: tt.method, Categories: categories, Teams: 1, Hitters: 2, Pitchers: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := categoryLines()
+			for i, value := range valuer.Value(lines) {
+				want := tt.want[lines[i].Name]
+				if len(value.Values) != len(want) {
+					t.Errorf("%s valued in %v, want %v", lines[i].Name, value.Values, want)
+				}
+				var total float64
+				for stat, points := range want {
+					total += points
+					if math.Abs(value.Values[stat]-points) > 1e-9 {
+						t.Errorf("%s %s = %v, want %v", lines[i].Name, stat, value.Values[stat], points)
+					}
+				}
+				if math.Abs(value.Total-total) > 1e-9 {
+					t.Errorf("%s total = %v, want %v", lines[i].Name, value.Total, total)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCategoryValuer(t *testing.T) {
+	valuer, err := NewCategoryValuer(models.CategorySettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := valuer.Settings()
+	if settings.Method != models.MethodZScore || settings.Teams != 12 || settings.Hitters != 13 || settings.Pitchers != 9 {
+		t.Errorf("defaults = %+v", settings)
+	}
+	want := []models.Stat{models.StatR, models.StatHR, models.StatRBI, models.StatSB, models.StatAVG}
+	if got := valuer.Categories("batter"); !slices.Equal(got, want) {
+		t.Errorf("batter categories = %v, want 5x5 roto's %v", got, want)
+	}
+
+	for _, settings := range []models.CategorySettings{
+		{Method: "points"},
+		{Teams: -1},
+		{Categories: []models.Category{{Position: "fielder", Stat: "HR"}}},
+		{Categories: []models.Category{{Position: "batter", Stat: "XBH"}}},
+		{Categories: []models.Category{{Position: "batter", Stat: "AVG", Volume: "XAB"}}},
+		{Method: models.MethodSGP, Categories: []models.Category{{Position: "batter", Stat: "HR"}}},
+	} {
+		if _, err := NewCategoryValuer(settings); err == nil {
+			t.Errorf("NewCategoryValuer(%+v) succeeded, want an error", settings)
+		}
+	}
+}
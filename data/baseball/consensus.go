@@ -0,0 +1,200 @@
+package baseball
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"super-fantasy-api/models"
+)
+
+// ConsensusSource is the source name on lines combined from several sources
+const ConsensusSource = "consensus"
+
+// defaultTrim is the share of values a trimmed mean drops from each end
+const defaultTrim = 0.2
+
+// Aggregator combines one player's projections from several sources, either stat by stat into
+// a single line or as final values (points). NewAggregator checks the settings once.
+type Aggregator struct {
+	settings models.AggregationSettings
+}
+
+// NewAggregator validates the aggregation settings, filling in the mean at the points level
+func NewAggregator(settings models.AggregationSettings) (*Aggregator, error) {
+	switch settings.Method {
+	case "":
+		settings.Method = models.AggregateMean
+	case models.AggregateMean, models.AggregateMedian, models.AggregateTrimmed:
+	default:
+		return nil, fmt.Errorf("method must be '%s', '%s' or '%s'", models.AggregateMean, models.AggregateMedian, models.AggregateTrimmed)
+	}
+	switch settings.Level {
+	case "":
+		settings.Level = models.LevelPoints
+	case models.LevelPoints, models.LevelStats:
+	default:
+		return nil, fmt.Errorf("level must be '%s' or '%s'", models.LevelPoints, models.LevelStats)
+	}
+	if settings.Trim < 0 || settings.Trim >= 0.5 {
+		return nil, fmt.Errorf("trim must be at least 0 and under 0.5")
+	}
+	if settings.Trim == 0 {
+		settings.Trim = defaultTrim
+	}
+	for name, weight := range settings.Weights {
+		if _, ok := LookupSource(name); !ok {
+			return nil, fmt.Errorf("unknown source %q in weights", name)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("weight for %s can't be negative", name)
+		}
+	}
+	return &Aggregator{settings: settings}, nil
+}
+
+// Level is "points" or "stats", the level sources are combined at
+func (a *Aggregator) Level() string {
+	return a.settings.Level
+}
+
+// Weight is a source's weight, 1 unless the settings say otherwise
+func (a *Aggregator) Weight(source string) float64 {
+	if weight, ok := a.settings.Weights[source]; ok {
+		return weight
+	}
+	return 1
+}
+
+// Line combines a player's lines into one: each stat is combined over the sources that
+// project it, so a stat only one source has still carries over. Rates are then recomputed
+// from the combined counts they're made of, keeping AVG in step with H and AB. The player's
+// identity is taken from the first line; sources weighted 0 are left out.
+func (a *Aggregator) Line(lines []Line) Line {
+	var included []Line
+	for _, line := range lines {
+		if a.Weight(line.Source) > 0 {
+			included = append(included, line)
+		}
+	}
+	if len(included) == 0 {
+		return Line{}
+	}
+	combined := included[0]
+	combined.Source = ConsensusSource
+	combined.Stats = make(map[models.Stat]float64)
+
+	values := make(map[models.Stat][]float64)
+	weights := make(map[models.Stat][]float64)
+	for _, line := range included {
+		for stat, value := range line.Stats {
+			values[stat] = append(values[stat], value)
+			weights[stat] = append(weights[stat], a.Weight(line.Source))
+		}
+	}
+	for stat := range values {
+		combined.Stats[stat] = a.combine(values[stat], weights[stat])
+	}
+	recomputeRates(combined)
+	return combined
+}
+
+// Combine combines one value per source (points), listing the sources that took part. sources
+// and values run in parallel; sources weighted 0 are left out.
+func (a *Aggregator) Combine(sources []string, values []float64) (float64, []string) {
+	var included []string
+	var kept, weights []float64
+	for i, source := range sources {
+		if weight := a.Weight(source); weight > 0 {
+			included = append(included, source)
+			kept = append(kept, values[i])
+			weights = append(weights, weight)
+		}
+	}
+	return a.combine(kept, weights), included
+}
+
+// Sources lists the sources of the lines that take part in Line
+func (a *Aggregator) Sources(lines []Line) []string {
+	var sources []string
+	for _, line := range lines {
+		if a.Weight(line.Source) > 0 {
+			sources = append(sources, line.Source)
+		}
+	}
+	return sources
+}
+
+func (a *Aggregator) combine(values, weights []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	switch a.settings.Method {
+	case models.AggregateMedian:
+		return medianOf(values)
+	case models.AggregateTrimmed:
+		order := make([]int, len(values))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+		// A trimmed mean of 3 or 4 sources would otherwise drop nothing
+		drop := int(math.Floor(a.settings.Trim * float64(len(values))))
+		if drop == 0 && len(values) >= 3 {
+			drop = 1
+		}
+		var kept, keptWeights []float64
+		for _, i := range order[drop : len(order)-drop] {
+			kept = append(kept, values[i])
+			keptWeights = append(keptWeights, weights[i])
+		}
+		return weightedMean(kept, keptWeights)
+	}
+	return weightedMean(values, weights)
+}
+
+func weightedMean(values, weights []float64) float64 {
+	var sum, total float64
+	for i, value := range values {
+		sum += value * weights[i]
+		total += weights[i]
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// Consensus combines one player's lines from several sources with an equally weighted mean
+// of each stat (see Aggregator.Line)
+func Consensus(lines []Line) Line {
+	aggregator, _ := NewAggregator(models.AggregationSettings{})
+	return aggregator.Line(lines)
+}
+
+// recomputeRates derives AVG, ERA and WHIP from the counting stats behind them, where the
+// line has them. This also fills in rates an export leaves out (Steamer has no WHIP column).
+func recomputeRates(line Line) {
+	s := line.Stats
+	has := func(stats ...models.Stat) bool {
+		for _, stat := range stats {
+			if !line.Has(stat) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if line.Position == "pitcher" {
+		if has(models.StatIP, models.StatER) && s[models.StatIP] > 0 {
+			s[models.StatERA] = 9 * s[models.StatER] / s[models.StatIP]
+		}
+		if has(models.StatIP, models.StatBB, models.StatH) && s[models.StatIP] > 0 {
+			s[models.StatWHIP] = (s[models.StatBB] + s[models.StatH]) / s[models.StatIP]
+		}
+		return
+	}
+	if has(models.StatAB, models.StatH) && s[models.StatAB] > 0 {
+		s[models.StatAVG] = s[models.StatH] / s[models.StatAB]
+	}
+}
@@ -0,0 +1,87 @@
+package baseball
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+func TestAggregatorCombine(t *testing.T) {
+	sources := []string{"fangraphs_steamer", "fangraphs_zips", "fantasypros", "fangraphs_atc", "fangraphs_thebat"}
+	values := []float64{100, 200, 300, 400, 1000}
+
+	tests := []struct {
+		name     string
+		settings models.AggregationSettings
+		sources  []string
+		values   []float64
+		want     float64
+		included []string
+	}{
+		{name: "mean", sources: sources, values: values, want: 400, included: sources},
+		{name: "median", settings: models.AggregationSettings{Method: models.AggregateMedian}, sources: sources, values: values, want: 300, included: sources},
+		{name: "median of even count", settings: models.AggregationSettings{Method: models.AggregateMedian}, sources: sources[:4], values: values[:4], want: 250, included: sources[:4]},
+		{name: "trimmed", settings: models.AggregationSettings{Method: models.AggregateTrimmed}, sources: sources, values: values, want: 300, included: sources},
+		{name: "trimmed of three drops both ends", settings: models.AggregationSettings{Method: models.AggregateTrimmed}, sources: sources[2:], values: values[2:], want: 400, included: sources[2:]},
+		{name: "trimmed of two keeps both", settings: models.AggregationSettings{Method: models.AggregateTrimmed}, sources: sources[3:], values: values[3:], want: 700, included: sources[3:]},
+		{name: "trimmed with a wider trim", settings: models.AggregationSettings{Method: models.AggregateTrimmed, Trim: 0.4}, sources: sources, values: values, want: 300, included: sources},
+		{
+			name:     "weighted mean leaves out zero weights",
+			settings: models.AggregationSettings{Weights: map[string]float64{"fangraphs_steamer": 3, "fangraphs_thebat": 0}},
+			sources:  sources,
+			values:   values,
+			want:     (3*100 + 200 + 300 + 400) / 6.0,
+			included: sources[:4],
+		},
+		{name: "no values", sources: nil, values: nil, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregator, err := NewAggregator(tt.settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, included := aggregator.Combine(tt.sources, tt.values)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Combine = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(included, tt.included) {
+				t.Errorf("included = %v, want %v", included, tt.included)
+			}
+		})
+	}
+}
+
+func TestAggregatorLine(t *testing.T) {
+	lines := []Line{
+		{Name: "Player", Source: "fangraphs_steamer", Position: "batter", Stats: map[models.Stat]float64{models.StatAB: 500, models.StatH: 150, models.StatHR: 30}},
+		{Name: "Player", Source: "fangraphs_zips", Position: "batter", Stats: map[models.Stat]float64{models.StatAB: 600, models.StatH: 150, models.StatSB: 10}},
+	}
+	line := Consensus(lines)
+	if line.Source != ConsensusSource || line.Name != "Player" {
+		t.Errorf("identity = %q from %q", line.Name, line.Source)
+	}
+	want := map[models.Stat]float64{models.StatAB: 550, models.StatH: 150, models.StatHR: 30, models.StatSB: 10, models.StatAVG: 150.0 / 550}
+	for stat, value := range want {
+		if math.Abs(line.Get(stat)-value) > 1e-9 {
+			t.Errorf("%s = %v, want %v", stat, line.Get(stat), value)
+		}
+	}
+}
+
+func TestNewAggregatorRejects(t *testing.T) {
+	for _, settings := range []models.AggregationSettings{
+		{Method: "mode"},
+		{Level: "team"},
+		{Trim: 0.5},
+		{Trim: -0.1},
+		{Weights: map[string]float64{"nope": 1}},
+		{Weights: map[string]float64{"fantasypros": -1}},
+	} {
+		if _, err := NewAggregator(settings); err == nil {
+			t.Errorf("NewAggregator(%+v) succeeded, want an error", settings)
+		}
+	}
+}
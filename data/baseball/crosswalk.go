@@ -0,0 +1,54 @@
+package baseball
+
+import (
+	"strings"
+
+	"super-fantasy-api/models"
+)
+
+// CrosswalkRow is one player from a player ID map, such as the SFBB Player ID Map
+// (PLAYERNAME, IDFANGRAPHS, MLBID, ...) or the Chadwick register (name_first, key_fangraphs, key_mlbam, ...)
+type CrosswalkRow struct {
+	Name            string `csv:"PLAYERNAME|Name,optional"`
+	FirstName       string `csv:"FIRSTNAME|name_first,optional"`
+	LastName        string `csv:"LASTNAME|name_last,optional"`
+	Team            string `csv:"TEAM,optional"`
+	Position        string `csv:"POS|Position,optional"`
+	MLBAMID         string `csv:"MLBID|key_mlbam|MLBAMID,optional"`
+	FanGraphsID     string `csv:"IDFANGRAPHS|key_fangraphs|PlayerId,optional"`
+	FantasyProsID   string `csv:"FANTPROSID|FantasyProsID|key_fantasypros,optional"`
+	FantasyProsName string `csv:"FANTPROSNAME,optional"`
+}
+
+// FullName is the player's display name, built from first and last name when the file has no full name column
+func (r CrosswalkRow) FullName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return strings.TrimSpace(r.FirstName + " " + r.LastName)
+}
+
+// IDs returns the external IDs on the row. Numeric IDs are exported as floats by some
+// spreadsheets ("660271.0"), so the trailing ".0" is dropped.
+func (r CrosswalkRow) IDs() models.PlayerIDs {
+	clean := func(id string) string {
+		return strings.TrimSuffix(strings.TrimSpace(id), ".0")
+	}
+	return models.PlayerIDs{
+		MLBAM:       clean(r.MLBAMID),
+		FanGraphs:   clean(r.FanGraphsID),
+		FantasyPros: clean(r.FantasyProsID),
+	}
+}
+
+// Role maps the row's position to "pitcher" or "batter", or "" when the file has no position
+func (r CrosswalkRow) Role() string {
+	switch strings.ToUpper(strings.TrimSpace(r.Position)) {
+	case "":
+		return ""
+	case "P", "SP", "RP":
+		return "pitcher"
+	default:
+		return "batter"
+	}
+}
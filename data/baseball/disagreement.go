@@ -0,0 +1,65 @@
+package baseball
+
+import (
+	"math"
+	"sort"
+)
+
+// defaultOutlierThreshold is how far, as a share of the other sources' median, a source must
+// be from it to count as an outlier
+const defaultOutlierThreshold = 0.2
+
+// Spread is how much the sources projecting a player disagree on a value (points)
+type Spread struct {
+	Sources  int
+	StdDev   float64
+	Min      float64
+	Max      float64
+	Range    float64
+	Outliers []string // sources far from the rest
+}
+
+// MeasureSpread summarizes one value per source; sources and values run in parallel. With at
+// least three sources, a source is an outlier when it sits more than threshold (a share, 0.2
+// for 20%) away from the median of the others. A threshold of 0 uses the default.
+func MeasureSpread(sources []string, values []float64, threshold float64) Spread {
+	spread := Spread{Sources: len(values)}
+	if len(values) == 0 {
+		return spread
+	}
+	if threshold <= 0 {
+		threshold = defaultOutlierThreshold
+	}
+
+	_, spread.StdDev = meanStdDev(values)
+	spread.Min, spread.Max = values[0], values[0]
+	for _, value := range values {
+		spread.Min = math.Min(spread.Min, value)
+		spread.Max = math.Max(spread.Max, value)
+	}
+	spread.Range = spread.Max - spread.Min
+
+	if len(values) < 3 {
+		return spread
+	}
+	for i, value := range values {
+		others := make([]float64, 0, len(values)-1)
+		others = append(others, values[:i]...)
+		others = append(others, values[i+1:]...)
+		median := medianOf(others)
+		if median != 0 && math.Abs(value-median)/math.Abs(median) > threshold {
+			spread.Outliers = append(spread.Outliers, sources[i])
+		}
+	}
+	return spread
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
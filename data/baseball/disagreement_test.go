@@ -0,0 +1,57 @@
+package baseball
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestMeasureSpread(t *testing.T) {
+	sources := []string{"steamer", "zips", "atc", "fantasypros"}
+	tests := []struct {
+		name      string
+		values    []float64
+		threshold float64
+		want      Spread
+	}{
+		{name: "no sources", want: Spread{}},
+		{name: "one source", values: []float64{100}, want: Spread{Sources: 1, Min: 100, Max: 100}},
+		{
+			name:   "two sources are never outliers",
+			values: []float64{100, 200},
+			want:   Spread{Sources: 2, StdDev: 50, Min: 100, Max: 200, Range: 100},
+		},
+		{
+			// atc is 27.5 off the others' 102.5; steamer 17.5 off 117.5 and zips 10 off 115 aren't
+			name:   "one outlier at the default threshold",
+			values: []float64{100, 105, 130},
+			want:   Spread{Sources: 3, StdDev: math.Sqrt(1550.0 / 9), Min: 100, Max: 130, Range: 30, Outliers: []string{"atc"}},
+		},
+		{
+			name:      "a looser threshold",
+			values:    []float64{100, 105, 130},
+			threshold: 0.3,
+			want:      Spread{Sources: 3, StdDev: math.Sqrt(1550.0 / 9), Min: 100, Max: 130, Range: 30},
+		},
+		{
+			name:   "both ends of a wide split",
+			values: []float64{50, 100, 100, 150},
+			want:   Spread{Sources: 4, StdDev: math.Sqrt(1250), Min: 50, Max: 150, Range: 100, Outliers: []string{"steamer", "fantasypros"}},
+		},
+		{
+			// A zero median has no scale to measure against
+			name:   "zero median",
+			values: []float64{0, 0, 0, 10},
+			want:   Spread{Sources: 4, StdDev: math.Sqrt(18.75), Min: 0, Max: 10, Range: 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MeasureSpread(sources[:len(tt.values)], tt.values, tt.threshold)
+			if got.Sources != tt.want.Sources || math.Abs(got.StdDev-tt.want.StdDev) > 1e-9 || got.Min != tt.want.Min ||
+				got.Max != tt.want.Max || got.Range != tt.want.Range || !slices.Equal(got.Outliers, tt.want.Outliers) {
+				t.Errorf("MeasureSpread = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
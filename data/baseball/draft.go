@@ -0,0 +1,95 @@
+package baseball
+
+import (
+	"fmt"
+	"slices"
+
+	"super-fantasy-api/models"
+)
+
+// SnakeTeam is the index of the team making a snake draft's pick, counting picks from 0: the
+// order runs forward in odd rounds and back in even ones
+func SnakeTeam(teams, pick int) int {
+	round, turn := pick/teams, pick%teams
+	if round%2 == 1 {
+		return teams - 1 - turn
+	}
+	return turn
+}
+
+// DraftComplete reports whether every roster in the draft is full
+func DraftComplete(draft models.Draft) bool {
+	return len(draft.Picks) >= draft.Spots*len(draft.Teams)
+}
+
+// OnTheClock is the team making the next pick of a snake draft, empty for an auction or once
+// every roster is full
+func OnTheClock(draft models.Draft) string {
+	if draft.Type != models.DraftSnake || len(draft.Teams) == 0 || DraftComplete(draft) {
+		return ""
+	}
+	return draft.Teams[SnakeTeam(len(draft.Teams), len(draft.Picks))]
+}
+
+// TeamRosters lists every team's picks, in draft order, with the spots and (for an auction)
+// budget they have left
+func TeamRosters(draft models.Draft) []models.TeamRoster {
+	rosters := make([]models.TeamRoster, len(draft.Teams))
+	for i, team := range draft.Teams {
+		rosters[i] = models.TeamRoster{Team: team, Picks: []models.DraftPick{}, Open: draft.Spots}
+	}
+	for _, pick := range draft.Picks {
+		i := slices.Index(draft.Teams, pick.Team)
+		if i < 0 {
+			continue
+		}
+		rosters[i].Picks = append(rosters[i].Picks, pick)
+		rosters[i].Open--
+		rosters[i].Spent += pick.Price
+	}
+	if draft.Type == models.DraftAuction {
+		for i := range rosters {
+			rosters[i].Remaining = draft.Budget - rosters[i].Spent
+			if rosters[i].Open > 0 {
+				rosters[i].MaxBid = rosters[i].Remaining - draft.MinBid*float64(rosters[i].Open-1)
+			}
+		}
+	}
+	return rosters
+}
+
+// CheckPick reports why a pick can't be made in the draft: an unknown team, a snake pick out
+// of turn, a full roster, a player already taken, or a price that's missing from an auction
+// (or given in a snake draft), under the minimum bid or more than the team can spend and
+// still fill its roster
+func CheckPick(draft models.Draft, pick models.DraftPick) error {
+	i := slices.Index(draft.Teams, pick.Team)
+	if i < 0 {
+		return fmt.Errorf("unknown team %q", pick.Team)
+	}
+	if clock := OnTheClock(draft); clock != "" && clock != pick.Team {
+		return fmt.Errorf("%s is on the clock, not %s", clock, pick.Team)
+	}
+	for _, taken := range draft.Picks {
+		if taken.PlayerID == pick.PlayerID {
+			return fmt.Errorf("%s was already taken by %s", taken.PlayerName, taken.Team)
+		}
+	}
+	roster := TeamRosters(draft)[i]
+	if roster.Open <= 0 {
+		return fmt.Errorf("%s's roster is full", pick.Team)
+	}
+	if draft.Type != models.DraftAuction {
+		if pick.Price != 0 {
+			return fmt.Errorf("prices are only for auction drafts")
+		}
+		return nil
+	}
+	if pick.Price < draft.MinBid {
+		return fmt.Errorf("price must be at least the $%g minimum bid", draft.MinBid)
+	}
+	if pick.Price > roster.MaxBid {
+		return fmt.Errorf("%s can bid at most $%g", pick.Team, roster.MaxBid)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package baseball
+
+import (
+	"slices"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+func TestSnakeTeam(t *testing.T) {
+	var order []int
+	for pick := 0; pick < 9; pick++ {
+		order = append(order, SnakeTeam(3, pick))
+	}
+	if want := []int{0, 1, 2, 2, 1, 0, 0, 1, 2}; !slices.Equal(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestCheckPickSnake(t *testing.T) {
+	draft := models.Draft{Type: models.DraftSnake, Teams: []string{"A", "B"}, Spots: 2}
+	picks := []struct {
+		team, player string
+		ok           bool
+	}{
+		{"B", "p1", false}, // A picks first
+		{"A", "p1", true},
+		{"A", "p2", false}, // B's turn
+		{"B", "p1", false}, // already taken
+		{"B", "p2", true},
+		{"B", "p3", true}, // B picks twice at the turn
+		{"A", "p4", true},
+		{"A", "p5", false}, // every roster is full
+	}
+	for _, p := range picks {
+		pick := models.DraftPick{Team: p.team, PlayerID: p.player, PlayerName: p.player}
+		err := CheckPick(draft, pick)
+		if (err == nil) != p.ok {
+			t.Fatalf("pick %d by %s of %s: err = %v, want ok %v", len(draft.Picks)+1, p.team, p.player, err, p.ok)
+		}
+		if err == nil {
+			draft.Picks = append(draft.Picks, pick)
+		}
+	}
+	if err := CheckPick(draft, models.DraftPick{Team: "C", PlayerID: "p6"}); err == nil {
+		t.Error("pick by an unknown team was allowed")
+	}
+	draft.Picks = draft.Picks[:1]
+	if err := CheckPick(draft, models.DraftPick{Team: "B", PlayerID: "p6", Price: 5}); err == nil {
+		t.Error("priced snake pick was allowed")
+	}
+}
+
+func TestCheckPickAuction(t *testing.T) {
+	draft := models.Draft{Type: models.DraftAuction, Teams: []string{"A", "B"}, Spots: 3, Budget: 10, MinBid: 1}
+	if err := CheckPick(draft, models.DraftPick{Team: "B", PlayerID: "x", Price: 8}); err != nil {
+		t.Errorf("auction picks can come in any order: %v", err)
+	}
+	if err := CheckPick(draft, models.DraftPick{Team: "A", PlayerID: "x", Price: 9}); err == nil {
+		t.Error("bid leaving too little for the roster was allowed")
+	}
+	if err := CheckPick(draft, models.DraftPick{Team: "A", PlayerID: "x", Price: 0.5}); err == nil {
+		t.Error("bid under the minimum was allowed")
+	}
+
+	draft.Picks = []models.DraftPick{{Team: "A", PlayerID: "x", PlayerName: "X", Price: 8}}
+	rosters := TeamRosters(draft)
+	if rosters[0].Remaining != 2 || rosters[0].MaxBid != 1 || rosters[0].Open != 2 {
+		t.Errorf("A's roster = %+v", rosters[0])
+	}
+	if err := CheckPick(draft, models.DraftPick{Team: "A", PlayerID: "y", Price: 2}); err == nil {
+		t.Error("bid over the max bid was allowed")
+	}
+	if OnTheClock(draft) != "" {
+		t.Error("auctions have no team on the clock")
+	}
+}
@@ -2,102 +2,212 @@ package baseball
 
 import (
 	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
 )
 
+func init() {
+	// "fangraphs" is the generic FanGraphs format; the systems share it and are told apart by suffix
+	for _, system := range []struct{ name, label string }{
+		{"fangraphs", "FanGraphs"},
+		{"fangraphs_steamer", "Steamer"},
+		{"fangraphs_zips", "ZiPS"},
+		{"fangraphs_zipsdc", "ZiPSDC"},
+		{"fangraphs_thebat", "TheBat"},
+		{"fangraphs_depthcharts", "DepthCharts"},
+		{"fangraphs_oopsy", "OOPSY"},
+	} {
+		RegisterSource(structSource{name: system.name, label: system.label, batter: newFangraphsBatter, pitcher: newFangraphsPitcher})
+	}
+	RegisterSource(structSource{name: "fangraphs_atc", label: "FangraphsATC", pitcher: newFangraphsPitcher})
+	RegisterSource(structSource{name: "fangraphs_batx", label: "FangraphsBatX", batter: newFangraphsBatter})
+}
+
+func newFangraphsBatter() Row  { return &FangraphsBatter{} }
+func newFangraphsPitcher() Row { return &FangraphsPitcher{} }
+
 // struct is based on batx rankings in fangraphs
 // https://www.fangraphs.com/projections?type=thebatx&stats=bat&pos=all&team=0&players=0&lg=all&z=1741170599&pageitems=30&statgroup=standard&fantasypreset=dashboard
 type FangraphsBatter struct {
-	Rank           int     `bson:"rank" json:"rank"`                       // # (position in list)
-	Name           string  `bson:"name" json:"name"`                       // Name
-	Team           string  `bson:"team" json:"team"`                       // Team
-	Games          float64 `bson:"games" json:"games"`                     // G
-	AtBats         float64 `bson:"at_bats" json:"at_bats"`                 // AB
-	PlateApps      float64 `bson:"plate_apps" json:"plate_apps"`           // PA
-	Hits           float64 `bson:"hits" json:"hits"`                       // H
-	Singles        float64 `bson:"singles" json:"singles"`                 // 1B
-	Doubles        float64 `bson:"doubles" json:"doubles"`                 // 2B
-	Triples        float64 `bson:"triples" json:"triples"`                 // 3B
-	HomeRuns       float64 `bson:"home_runs" json:"home_runs"`             // HR
-	Runs           float64 `bson:"runs" json:"runs"`                       // R
-	RBI            float64 `bson:"rbi" json:"rbi"`                         // RBI
-	Walks          float64 `bson:"walks" json:"walks"`                     // BB
-	IntWalks       float64 `bson:"int_walks" json:"int_walks"`             // IBB (Intentional Walks)
-	Strikeouts     float64 `bson:"strikeouts" json:"strikeouts"`           // SO
-	HitByPitch     float64 `bson:"hit_by_pitch" json:"hit_by_pitch"`       // HBP
-	SacFlies       float64 `bson:"sac_flies" json:"sac_flies"`             // SF
-	SacHits        float64 `bson:"sac_hits" json:"sac_hits"`               // SH
-	StolenBases    float64 `bson:"stolen_bases" json:"stolen_bases"`       // SB
-	CaughtStealing float64 `bson:"caught_stealing" json:"caught_stealing"` // CS
-	AVG            float64 `bson:"avg" json:"avg"`                         // AVG
-	Year           string  `bson:"year" json:"year"`                       // year
-	Source         string  `bson:"source" json:"source"`                   // source
-	Position       string  `bson:"position" json:"position"`               // position
+	Rank           int           `bson:"rank" json:"rank" csv:"#|Rank,optional"`                                       // # (position in list)
+	Name           string        `bson:"name" json:"name" csv:"Name|Player"`                                           // Name
+	Team           string        `bson:"team" json:"team" csv:"Team"`                                                  // Team
+	Games          float64       `bson:"games" json:"games" csv:"G"`                                                   // G
+	AtBats         float64       `bson:"at_bats" json:"at_bats" csv:"AB"`                                              // AB
+	PlateApps      float64       `bson:"plate_apps" json:"plate_apps" csv:"PA"`                                        // PA
+	Hits           float64       `bson:"hits" json:"hits" csv:"H"`                                                     // H
+	Singles        float64       `bson:"singles" json:"singles" csv:"1B"`                                              // 1B
+	Doubles        float64       `bson:"doubles" json:"doubles" csv:"2B"`                                              // 2B
+	Triples        float64       `bson:"triples" json:"triples" csv:"3B"`                                              // 3B
+	HomeRuns       float64       `bson:"home_runs" json:"home_runs" csv:"HR"`                                          // HR
+	Runs           float64       `bson:"runs" json:"runs" csv:"R"`                                                     // R
+	RBI            float64       `bson:"rbi" json:"rbi" csv:"RBI"`                                                     // RBI
+	Walks          float64       `bson:"walks" json:"walks" csv:"BB"`                                                  // BB
+	IntWalks       float64       `bson:"int_walks" json:"int_walks" csv:"IBB,optional"`                                // IBB (Intentional Walks)
+	Strikeouts     float64       `bson:"strikeouts" json:"strikeouts" csv:"SO|K"`                                      // SO
+	HitByPitch     float64       `bson:"hit_by_pitch" json:"hit_by_pitch" csv:"HBP,optional"`                          // HBP
+	SacFlies       float64       `bson:"sac_flies" json:"sac_flies" csv:"SF,optional"`                                 // SF
+	SacHits        float64       `bson:"sac_hits" json:"sac_hits" csv:"SH,optional"`                                   // SH
+	StolenBases    float64       `bson:"stolen_bases" json:"stolen_bases" csv:"SB"`                                    // SB
+	CaughtStealing float64       `bson:"caught_stealing" json:"caught_stealing" csv:"CS,optional"`                     // CS
+	GroundedIntoDP float64       `bson:"gidp,omitempty" json:"gidp,omitempty" csv:"GIDP|GDP,optional"`                 // GDP
+	AVG            float64       `bson:"avg" json:"avg" csv:"AVG"`                                                     // AVG
+	OBP            float64       `bson:"obp,omitempty" json:"obp,omitempty" csv:"OBP,optional"`                        // OBP
+	SLG            float64       `bson:"slg,omitempty" json:"slg,omitempty" csv:"SLG,optional"`                        // SLG
+	OPS            float64       `bson:"ops,omitempty" json:"ops,omitempty" csv:"OPS,optional"`                        // OPS
+	WOBA           float64       `bson:"woba,omitempty" json:"woba,omitempty" csv:"wOBA,optional"`                     // wOBA
+	WAR            float64       `bson:"war,omitempty" json:"war,omitempty" csv:"WAR,optional"`                        // WAR
+	Year           string        `bson:"year" json:"year"`                                                             // year
+	Source         string        `bson:"source" json:"source"`                                                         // source
+	Position       string        `bson:"position" json:"position"`                                                     // position
+	PlayerID       string        `bson:"player_id,omitempty" json:"player_id,omitempty"`                               // canonical player ID from the registry
+	Unprojected    []models.Stat `bson:"unprojected,omitempty" json:"unprojected,omitempty"`                           // stats the export left out or blank
+	FanGraphsID    string        `bson:"fangraphs_id,omitempty" json:"fangraphs_id,omitempty" csv:"PlayerId,optional"` // FanGraphs player ID (full exports only)
+	MLBAMID        string        `bson:"mlbam_id,omitempty" json:"mlbam_id,omitempty" csv:"MLBAMID|xMLBAMID,optional"` // MLBAM ID (full exports only)
 }
 
 // struct is based on atc rankings in fangraphs
 // https://www.fangraphs.com/fantasy-tools/auction-calculator?teams=12&lg=MLB&dollars=260&mb=1&mp=20&msp=5&mrp=5&type=pit&players=&proj=atc&split=&points=c%7C1%2C2%2C3%2C4%2C5%2C7%7C0%2C13%2C14%2C2%2C3%2C4%2C6&rep=0&drp=0&pp=SS%2C2B%2C3B%2COF%2C1B%2CC&pos=1%2C1%2C1%2C1%2C4%2C1%2C0%2C0%2C1%2C1%2C3%2C2%2C4%2C5%2C0&sort=&view=0
 type FangraphsPitcher struct {
-	Rank              int     `bson:"rank" json:"rank"`                               // #
-	Name              string  `bson:"name" json:"name"`                               // Name
-	Team              string  `bson:"team" json:"team"`                               // Team
-	Wins              float64 `bson:"wins" json:"wins"`                               // W
-	Losses            float64 `bson:"losses" json:"losses"`                           // L
-	ERA               float64 `bson:"era" json:"era"`                                 // ERA
-	Games             float64 `bson:"games" json:"games"`                             // G
-	GamesStarted      float64 `bson:"games_started" json:"games_started"`             // GS
-	Saves             float64 `bson:"saves" json:"saves"`                             // SV
-	Holds             float64 `bson:"holds" json:"holds"`                             // HLD
-	BlownSaves        float64 `bson:"blown_saves" json:"blown_saves"`                 // BS
-	InningsPitched    float64 `bson:"innings_pitched" json:"innings_pitched"`         // IP
-	TotalBattersFaced float64 `bson:"total_batters_faced" json:"total_batters_faced"` // TBF
-	HitsAllowed       float64 `bson:"hits_allowed" json:"hits_allowed"`               // H
-	RunsAllowed       float64 `bson:"runs_allowed" json:"runs_allowed"`               // R
-	EarnedRuns        float64 `bson:"earned_runs" json:"earned_runs"`                 // ER
-	HomeRunsAllowed   float64 `bson:"home_runs_allowed" json:"home_runs_allowed"`     // HR
-	Walks             float64 `bson:"walks" json:"walks"`                             // BB
-	IntWalks          float64 `bson:"int_walks" json:"int_walks"`                     // IBB
-	HitByPitch        float64 `bson:"hit_by_pitch" json:"hit_by_pitch"`               // HBP
-	Strikeouts        float64 `bson:"strikeouts" json:"strikeouts"`                   // SO
-	Year              string  `bson:"year" json:"year"`                               // year
-	Source            string  `bson:"source" json:"source"`                           // source
-	Position          string  `bson:"position" json:"position"`                       // position
+	Rank              int           `bson:"rank" json:"rank" csv:"#|Rank,optional"`                                       // #
+	Name              string        `bson:"name" json:"name" csv:"Name|Player"`                                           // Name
+	Team              string        `bson:"team" json:"team" csv:"Team"`                                                  // Team
+	Wins              float64       `bson:"wins" json:"wins" csv:"W"`                                                     // W
+	Losses            float64       `bson:"losses" json:"losses" csv:"L"`                                                 // L
+	ERA               float64       `bson:"era" json:"era" csv:"ERA"`                                                     // ERA
+	Games             float64       `bson:"games" json:"games" csv:"G"`                                                   // G
+	GamesStarted      float64       `bson:"games_started" json:"games_started" csv:"GS"`                                  // GS
+	QualityStarts     float64       `bson:"quality_starts,omitempty" json:"quality_starts,omitempty" csv:"QS,optional"`   // QS (ZiPS, Depth Charts, OOPSY)
+	Saves             float64       `bson:"saves" json:"saves" csv:"SV"`                                                  // SV
+	Holds             float64       `bson:"holds" json:"holds" csv:"HLD,optional"`                                        // HLD
+	BlownSaves        float64       `bson:"blown_saves" json:"blown_saves" csv:"BS,optional"`                             // BS
+	InningsPitched    float64       `bson:"innings_pitched" json:"innings_pitched"`                                       // IP as true innings (Outs / 3)
+	Outs              utils.Outs    `bson:"outs" json:"outs" csv:"IP"`                                                    // IP, written in baseball notation (173.1 = 173⅓)
+	TotalBattersFaced float64       `bson:"total_batters_faced" json:"total_batters_faced" csv:"TBF,optional"`            // TBF
+	HitsAllowed       float64       `bson:"hits_allowed" json:"hits_allowed" csv:"H"`                                     // H
+	RunsAllowed       float64       `bson:"runs_allowed" json:"runs_allowed" csv:"R"`                                     // R
+	EarnedRuns        float64       `bson:"earned_runs" json:"earned_runs" csv:"ER"`                                      // ER
+	HomeRunsAllowed   float64       `bson:"home_runs_allowed" json:"home_runs_allowed" csv:"HR"`                          // HR
+	Walks             float64       `bson:"walks" json:"walks" csv:"BB"`                                                  // BB
+	IntWalks          float64       `bson:"int_walks" json:"int_walks" csv:"IBB,optional"`                                // IBB
+	HitByPitch        float64       `bson:"hit_by_pitch" json:"hit_by_pitch" csv:"HBP,optional"`                          // HBP
+	Strikeouts        float64       `bson:"strikeouts" json:"strikeouts" csv:"SO|K"`                                      // SO
+	WHIP              float64       `bson:"whip,omitempty" json:"whip,omitempty" csv:"WHIP,optional"`                     // WHIP
+	WAR               float64       `bson:"war,omitempty" json:"war,omitempty" csv:"WAR,optional"`                        // WAR
+	Year              string        `bson:"year" json:"year"`                                                             // year
+	Source            string        `bson:"source" json:"source"`                                                         // source
+	Position          string        `bson:"position" json:"position"`                                                     // position
+	PlayerID          string        `bson:"player_id,omitempty" json:"player_id,omitempty"`                               // canonical player ID from the registry
+	Unprojected       []models.Stat `bson:"unprojected,omitempty" json:"unprojected,omitempty"`                           // stats the export left out or blank
+	FanGraphsID       string        `bson:"fangraphs_id,omitempty" json:"fangraphs_id,omitempty" csv:"PlayerId,optional"` // FanGraphs player ID (full exports only)
+	MLBAMID           string        `bson:"mlbam_id,omitempty" json:"mlbam_id,omitempty" csv:"MLBAMID|xMLBAMID,optional"` // MLBAM ID (full exports only)
 }
 
-// CalculatePoints converts FanGraphs projections to fantasy points using league settings
-func CalculateBatterPoints(player FangraphsBatter, settings models.LeagueSettings) models.PlayerProjection {
-	// TODO: add conditionalizing for other types of league settings
-	// for now, we leave what we use
-	totalPoints := 0.0
-	totalPoints += player.Runs * settings.Batting.RunsScored
-	totalPoints += (player.Singles * settings.Batting.TotalBases) + (player.Doubles * (2 * settings.Batting.TotalBases)) + (player.Triples * (3 * settings.Batting.TotalBases)) + (player.HomeRuns * (4 * settings.Batting.TotalBases))
-	totalPoints += player.RBI * settings.Batting.RunsBattedIn
-	totalPoints += player.Walks * settings.Batting.Walks
-	totalPoints += player.Strikeouts * settings.Batting.Strikeouts
-	totalPoints += player.StolenBases * settings.Batting.StolenBases
+// BattingLine normalizes a FanGraphs batter projection
+func (player FangraphsBatter) BattingLine() BattingLine {
+	return NewBattingLine(Line{
+		PlayerID: player.PlayerID,
+		Name:     player.Name,
+		Team:     player.Team,
+		Year:     player.Year,
+		Source:   player.Source,
+		IDs:      models.PlayerIDs{MLBAM: player.MLBAMID, FanGraphs: player.FanGraphsID},
+		Stats: projected(map[models.Stat]float64{
+			models.StatG:    player.Games,
+			models.StatAB:   player.AtBats,
+			models.StatPA:   player.PlateApps,
+			models.StatH:    player.Hits,
+			models.Stat1B:   player.Singles,
+			models.Stat2B:   player.Doubles,
+			models.Stat3B:   player.Triples,
+			models.StatHR:   player.HomeRuns,
+			models.StatR:    player.Runs,
+			models.StatRBI:  player.RBI,
+			models.StatBB:   player.Walks,
+			models.StatIBB:  player.IntWalks,
+			models.StatSO:   player.Strikeouts,
+			models.StatHBP:  player.HitByPitch,
+			models.StatSF:   player.SacFlies,
+			models.StatSH:   player.SacHits,
+			models.StatSB:   player.StolenBases,
+			models.StatCS:   player.CaughtStealing,
+			models.StatGIDP: player.GroundedIntoDP,
+			models.StatAVG:  player.AVG,
+			models.StatOBP:  player.OBP,
+			models.StatSLG:  player.SLG,
+			models.StatOPS:  player.OPS,
+			models.StatWOBA: player.WOBA,
+			models.StatWAR:  player.WAR,
+		}, player.Unprojected),
+	})
+}
 
-	return models.PlayerProjection{
-		PlayerName:  player.Name,
-		TotalPoints: totalPoints,
-	}
+// PitchingLine normalizes a FanGraphs pitcher projection
+func (player FangraphsPitcher) PitchingLine() PitchingLine {
+	return NewPitchingLine(Line{
+		PlayerID: player.PlayerID,
+		Name:     player.Name,
+		Team:     player.Team,
+		Year:     player.Year,
+		Source:   player.Source,
+		IDs:      models.PlayerIDs{MLBAM: player.MLBAMID, FanGraphs: player.FanGraphsID},
+		Stats: projected(map[models.Stat]float64{
+			models.StatW:    player.Wins,
+			models.StatL:    player.Losses,
+			models.StatERA:  player.ERA,
+			models.StatG:    player.Games,
+			models.StatGS:   player.GamesStarted,
+			models.StatSV:   player.Saves,
+			models.StatHLD:  player.Holds,
+			models.StatBS:   player.BlownSaves,
+			models.StatIP:   player.Outs.Innings(),
+			models.StatOuts: float64(player.Outs),
+			models.StatTBF:  player.TotalBattersFaced,
+			models.StatH:    player.HitsAllowed,
+			models.StatR:    player.RunsAllowed,
+			models.StatER:   player.EarnedRuns,
+			models.StatHR:   player.HomeRunsAllowed,
+			models.StatBB:   player.Walks,
+			models.StatIBB:  player.IntWalks,
+			models.StatHBP:  player.HitByPitch,
+			models.StatSO:   player.Strikeouts,
+			models.StatQS:   player.QualityStarts,
+			models.StatWHIP: player.WHIP,
+			models.StatWAR:  player.WAR,
+		}, player.Unprojected),
+	})
+}
+
+// Normalize stamps an uploaded FanGraphs batter row
+func (player *FangraphsBatter) Normalize(meta RowMeta) {
+	player.Team = models.CanonicalTeam(player.Team)
+	player.Year = meta.Year
+	player.Source = meta.Source
+	player.Position = meta.Position
+	player.Unprojected = meta.Unprojected
+}
+
+// Normalize stamps an uploaded FanGraphs pitcher row; IP arrives in baseball notation as Outs
+func (player *FangraphsPitcher) Normalize(meta RowMeta) {
+	player.Team = models.CanonicalTeam(player.Team)
+	player.InningsPitched = player.Outs.Innings()
+	player.Year = meta.Year
+	player.Source = meta.Source
+	player.Position = meta.Position
+	player.Unprojected = meta.Unprojected
 }
 
-// CalculatePoints converts FanGraphs projections to fantasy points using league settings
-func CalculatePitcherPoints(player FangraphsPitcher, settings models.LeagueSettings) models.PlayerProjection {
-	// TODO: add conditionalizing for other types of league settings
-	// for now, we leave what we use
-	totalPoints := 0.0
-	totalPoints += player.Strikeouts * settings.Pitching.Strikeouts
-	totalPoints += player.InningsPitched * settings.Pitching.InningsPitched
-	totalPoints += player.HitsAllowed * settings.Pitching.HitsAllowed
-	totalPoints += player.EarnedRuns * settings.Pitching.EarnedRuns
-	totalPoints += player.Walks * settings.Pitching.WalksIssued
-	totalPoints += player.Wins * settings.Pitching.Wins
-	totalPoints += player.Losses * settings.Pitching.Losses
-	totalPoints += player.Saves * settings.Pitching.Saves
-	totalPoints += player.Holds * settings.Pitching.Holds
+func (player *FangraphsBatter) Link(playerID string)  { player.PlayerID = playerID }
+func (player *FangraphsPitcher) Link(playerID string) { player.PlayerID = playerID }
 
-	return models.PlayerProjection{
-		PlayerName:  player.Name,
-		TotalPoints: totalPoints,
-	}
+func (player *FangraphsBatter) Line() Line  { return player.BattingLine().Line }
+func (player *FangraphsPitcher) Line() Line { return player.PitchingLine().Line }
+
+// CalculateBatterPoints converts FanGraphs projections to fantasy points using league settings
+func CalculateBatterPoints(player FangraphsBatter, settings models.LeagueSettings) (models.PlayerProjection, error) {
+	return Score(player.BattingLine().Line, settings)
+}
+
+// CalculatePitcherPoints converts FanGraphs projections to fantasy points using league settings
+func CalculatePitcherPoints(player FangraphsPitcher, settings models.LeagueSettings) (models.PlayerProjection, error) {
+	return Score(player.PitchingLine().Line, settings)
 }
@@ -2,90 +2,166 @@ package baseball
 
 import (
 	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
 )
 
+func init() {
+	RegisterSource(structSource{
+		name:    "fantasypros",
+		label:   "FantasyPros",
+		batter:  func() Row { return &FantasyProsBatter{} },
+		pitcher: func() Row { return &FantasyProsPitcher{} },
+	})
+}
+
 // Batter represents a FantasyPros batter projection
 // Based on CSV: "Player","Team","Positions","AB","R","HR","RBI","SB","AVG","OBP","H","2B","3B","BB","SO","SLG","OPS"
 type FantasyProsBatter struct {
-	Name        string  `bson:"name" json:"name"`                 // Player
-	Team        string  `bson:"team" json:"team"`                 // Team
-	Positions   string  `bson:"positions" json:"positions"`       // Positions
-	AtBats      float64 `bson:"at_bats" json:"at_bats"`           // AB
-	Runs        float64 `bson:"runs" json:"runs"`                 // R
-	HomeRuns    float64 `bson:"home_runs" json:"home_runs"`       // HR
-	RBI         float64 `bson:"rbi" json:"rbi"`                   // RBI
-	StolenBases float64 `bson:"stolen_bases" json:"stolen_bases"` // SB
-	AVG         float64 `bson:"avg" json:"avg"`                   // AVG
-	OBP         float64 `bson:"obp" json:"obp"`                   // OBP
-	Hits        float64 `bson:"hits" json:"hits"`                 // H
-	Doubles     float64 `bson:"doubles" json:"doubles"`           // 2B
-	Triples     float64 `bson:"triples" json:"triples"`           // 3B
-	Walks       float64 `bson:"walks" json:"walks"`               // BB
-	Strikeouts  float64 `bson:"strikeouts" json:"strikeouts"`     // SO
-	SLG         float64 `bson:"slg" json:"slg"`                   // SLG
-	OPS         float64 `bson:"ops" json:"ops"`                   // OPS
-	Year        string  `bson:"year" json:"year"`                 // year
-	Source      string  `bson:"source" json:"source"`             // source
-	Position    string  `bson:"position" json:"position"`         // position
+	Name          string        `bson:"name" json:"name" csv:"Player|Name"`                                                     // Player
+	Team          string        `bson:"team" json:"team" csv:"Team"`                                                            // Team
+	Positions     string        `bson:"positions" json:"positions" csv:"Positions|Pos"`                                         // Positions
+	AtBats        float64       `bson:"at_bats" json:"at_bats" csv:"AB"`                                                        // AB
+	Runs          float64       `bson:"runs" json:"runs" csv:"R"`                                                               // R
+	HomeRuns      float64       `bson:"home_runs" json:"home_runs" csv:"HR"`                                                    // HR
+	RBI           float64       `bson:"rbi" json:"rbi" csv:"RBI"`                                                               // RBI
+	StolenBases   float64       `bson:"stolen_bases" json:"stolen_bases" csv:"SB"`                                              // SB
+	AVG           float64       `bson:"avg" json:"avg" csv:"AVG"`                                                               // AVG
+	OBP           float64       `bson:"obp" json:"obp" csv:"OBP"`                                                               // OBP
+	Hits          float64       `bson:"hits" json:"hits" csv:"H"`                                                               // H
+	Doubles       float64       `bson:"doubles" json:"doubles" csv:"2B"`                                                        // 2B
+	Triples       float64       `bson:"triples" json:"triples" csv:"3B"`                                                        // 3B
+	Walks         float64       `bson:"walks" json:"walks" csv:"BB"`                                                            // BB
+	Strikeouts    float64       `bson:"strikeouts" json:"strikeouts" csv:"SO|K"`                                                // SO
+	SLG           float64       `bson:"slg" json:"slg" csv:"SLG"`                                                               // SLG
+	OPS           float64       `bson:"ops" json:"ops" csv:"OPS"`                                                               // OPS
+	Year          string        `bson:"year" json:"year"`                                                                       // year
+	Source        string        `bson:"source" json:"source"`                                                                   // source
+	Position      string        `bson:"position" json:"position"`                                                               // position
+	PlayerID      string        `bson:"player_id,omitempty" json:"player_id,omitempty"`                                         // canonical player ID from the registry
+	Unprojected   []models.Stat `bson:"unprojected,omitempty" json:"unprojected,omitempty"`                                     // stats the export left blank
+	FantasyProsID string        `bson:"fantasypros_id,omitempty" json:"fantasypros_id,omitempty" csv:"Player ID|FPID,optional"` // FantasyPros player ID, when exported
 }
 
 // Pitcher represents a FantasyPros pitcher projection
 // Based on CSV: "Player","Team","Positions","IP","K","W","SV","ERA","WHIP","ER","H","BB","HR","G","GS","L","CG"
 type FantasyProsPitcher struct {
-	Name            string  `bson:"name" json:"name"`                           // Player
-	Team            string  `bson:"team" json:"team"`                           // Team
-	Positions       string  `bson:"positions" json:"positions"`                 // Positions
-	InningsPitched  float64 `bson:"innings_pitched" json:"innings_pitched"`     // IP
-	Strikeouts      float64 `bson:"strikeouts" json:"strikeouts"`               // K
-	Wins            float64 `bson:"wins" json:"wins"`                           // W
-	Saves           float64 `bson:"saves" json:"saves"`                         // SV
-	ERA             float64 `bson:"era" json:"era"`                             // ERA
-	WHIP            float64 `bson:"whip" json:"whip"`                           // WHIP
-	EarnedRuns      float64 `bson:"earned_runs" json:"earned_runs"`             // ER
-	HitsAllowed     float64 `bson:"hits_allowed" json:"hits_allowed"`           // H
-	Walks           float64 `bson:"walks" json:"walks"`                         // BB
-	HomeRunsAllowed float64 `bson:"home_runs_allowed" json:"home_runs_allowed"` // HR
-	Games           float64 `bson:"games" json:"games"`                         // G
-	GamesStarted    float64 `bson:"games_started" json:"games_started"`         // GS
-	Losses          float64 `bson:"losses" json:"losses"`                       // L
-	CompleteGames   float64 `bson:"complete_games" json:"complete_games"`       // CG
-	Year            string  `bson:"year" json:"year"`                           // year
-	Source          string  `bson:"source" json:"source"`                       // source
-	Position        string  `bson:"position" json:"position"`                   // position
+	Name            string        `bson:"name" json:"name" csv:"Player|Name"`                                                     // Player
+	Team            string        `bson:"team" json:"team" csv:"Team"`                                                            // Team
+	Positions       string        `bson:"positions" json:"positions" csv:"Positions|Pos"`                                         // Positions
+	InningsPitched  float64       `bson:"innings_pitched" json:"innings_pitched" csv:"IP"`                                        // IP (FantasyPros exports true decimal innings)
+	Outs            utils.Outs    `bson:"outs" json:"outs"`                                                                       // outs recorded, derived from IP
+	Strikeouts      float64       `bson:"strikeouts" json:"strikeouts" csv:"K|SO"`                                                // K
+	Wins            float64       `bson:"wins" json:"wins" csv:"W"`                                                               // W
+	Saves           float64       `bson:"saves" json:"saves" csv:"SV"`                                                            // SV
+	ERA             float64       `bson:"era" json:"era" csv:"ERA"`                                                               // ERA
+	WHIP            float64       `bson:"whip" json:"whip" csv:"WHIP"`                                                            // WHIP
+	EarnedRuns      float64       `bson:"earned_runs" json:"earned_runs" csv:"ER"`                                                // ER
+	HitsAllowed     float64       `bson:"hits_allowed" json:"hits_allowed" csv:"H"`                                               // H
+	Walks           float64       `bson:"walks" json:"walks" csv:"BB"`                                                            // BB
+	HomeRunsAllowed float64       `bson:"home_runs_allowed" json:"home_runs_allowed" csv:"HR"`                                    // HR
+	Games           float64       `bson:"games" json:"games" csv:"G"`                                                             // G
+	GamesStarted    float64       `bson:"games_started" json:"games_started" csv:"GS"`                                            // GS
+	Losses          float64       `bson:"losses" json:"losses" csv:"L"`                                                           // L
+	CompleteGames   float64       `bson:"complete_games" json:"complete_games" csv:"CG"`                                          // CG
+	Year            string        `bson:"year" json:"year"`                                                                       // year
+	Source          string        `bson:"source" json:"source"`                                                                   // source
+	Position        string        `bson:"position" json:"position"`                                                               // position
+	PlayerID        string        `bson:"player_id,omitempty" json:"player_id,omitempty"`                                         // canonical player ID from the registry
+	Unprojected     []models.Stat `bson:"unprojected,omitempty" json:"unprojected,omitempty"`                                     // stats the export left blank
+	FantasyProsID   string        `bson:"fantasypros_id,omitempty" json:"fantasypros_id,omitempty" csv:"Player ID|FPID,optional"` // FantasyPros player ID, when exported
 }
 
-// CalculateFantasyProsBatterPoints converts FantasyPros batter projections to fantasy points using league settings
-func CalculateFantasyProsBatterPoints(player FantasyProsBatter, settings models.LeagueSettings) models.PlayerProjection {
-	totalPoints := 0.0
-	totalPoints += player.Runs * settings.Batting.RunsScored
-	// Total Bases: H = 1B + 2B + 3B + HR, but HR already counted separately in sample data
-	// Since Singles isn't provided, approximate Total Bases using Hits and extra bases
-	totalPoints += ((player.Hits - player.Doubles - player.Triples - player.HomeRuns) * settings.Batting.TotalBases) + (player.Doubles * (2 * settings.Batting.TotalBases)) + (player.Triples * (3 * settings.Batting.TotalBases)) + (player.HomeRuns * (4 * settings.Batting.TotalBases))
-	totalPoints += player.RBI * settings.Batting.RunsBattedIn
-	totalPoints += player.Walks * settings.Batting.Walks
-	totalPoints += player.Strikeouts * settings.Batting.Strikeouts
-	totalPoints += player.StolenBases * settings.Batting.StolenBases
+// BattingLine normalizes a FantasyPros batter projection. FantasyPros doesn't project singles
+// (they're derived from hits), games, plate appearances, or caught stealing.
+func (player FantasyProsBatter) BattingLine() BattingLine {
+	return NewBattingLine(Line{
+		PlayerID:  player.PlayerID,
+		Name:      player.Name,
+		Team:      player.Team,
+		Positions: player.Positions,
+		Year:      player.Year,
+		Source:    player.Source,
+		IDs:       models.PlayerIDs{FantasyPros: player.FantasyProsID},
+		Stats: projected(map[models.Stat]float64{
+			models.StatAB:  player.AtBats,
+			models.StatR:   player.Runs,
+			models.StatHR:  player.HomeRuns,
+			models.StatRBI: player.RBI,
+			models.StatSB:  player.StolenBases,
+			models.StatAVG: player.AVG,
+			models.StatOBP: player.OBP,
+			models.StatH:   player.Hits,
+			models.Stat2B:  player.Doubles,
+			models.Stat3B:  player.Triples,
+			models.StatBB:  player.Walks,
+			models.StatSO:  player.Strikeouts,
+			models.StatSLG: player.SLG,
+			models.StatOPS: player.OPS,
+		}, player.Unprojected),
+	})
+}
 
-	return models.PlayerProjection{
-		PlayerName:  player.Name,
-		TotalPoints: totalPoints,
-	}
+// PitchingLine normalizes a FantasyPros pitcher projection. FantasyPros doesn't project holds.
+func (player FantasyProsPitcher) PitchingLine() PitchingLine {
+	return NewPitchingLine(Line{
+		PlayerID:  player.PlayerID,
+		Name:      player.Name,
+		Team:      player.Team,
+		Positions: player.Positions,
+		Year:      player.Year,
+		Source:    player.Source,
+		IDs:       models.PlayerIDs{FantasyPros: player.FantasyProsID},
+		Stats: projected(map[models.Stat]float64{
+			models.StatIP:   player.InningsPitched,
+			models.StatOuts: float64(utils.OutsFromInnings(player.InningsPitched)),
+			models.StatSO:   player.Strikeouts,
+			models.StatW:    player.Wins,
+			models.StatSV:   player.Saves,
+			models.StatERA:  player.ERA,
+			models.StatWHIP: player.WHIP,
+			models.StatER:   player.EarnedRuns,
+			models.StatH:    player.HitsAllowed,
+			models.StatBB:   player.Walks,
+			models.StatHR:   player.HomeRunsAllowed,
+			models.StatG:    player.Games,
+			models.StatGS:   player.GamesStarted,
+			models.StatL:    player.Losses,
+			models.StatCG:   player.CompleteGames,
+		}, player.Unprojected),
+	})
 }
 
-// CalculateFantasyProsPitcherPoints converts FantasyPros pitcher projections to fantasy points using league settings
-func CalculateFantasyProsPitcherPoints(player FantasyProsPitcher, settings models.LeagueSettings) models.PlayerProjection {
-	totalPoints := 0.0
-	totalPoints += player.Strikeouts * settings.Pitching.Strikeouts
-	totalPoints += player.InningsPitched * settings.Pitching.InningsPitched
-	totalPoints += player.HitsAllowed * settings.Pitching.HitsAllowed
-	totalPoints += player.EarnedRuns * settings.Pitching.EarnedRuns
-	totalPoints += player.Walks * settings.Pitching.WalksIssued
-	totalPoints += player.Wins * settings.Pitching.Wins
-	totalPoints += player.Losses * settings.Pitching.Losses
-	totalPoints += player.Saves * settings.Pitching.Saves
+// Normalize stamps an uploaded FantasyPros batter row
+func (player *FantasyProsBatter) Normalize(meta RowMeta) {
+	player.Team = models.CanonicalTeam(player.Team)
+	player.Year = meta.Year
+	player.Source = meta.Source
+	player.Position = meta.Position
+	player.Unprojected = meta.Unprojected
+}
+
+// Normalize stamps an uploaded FantasyPros pitcher row; IP is already decimal innings
+func (player *FantasyProsPitcher) Normalize(meta RowMeta) {
+	player.Team = models.CanonicalTeam(player.Team)
+	player.Outs = utils.OutsFromInnings(player.InningsPitched)
+	player.Year = meta.Year
+	player.Source = meta.Source
+	player.Position = meta.Position
+	player.Unprojected = meta.Unprojected
+}
+
+func (player *FantasyProsBatter) Link(playerID string)  { player.PlayerID = playerID }
+func (player *FantasyProsPitcher) Link(playerID string) { player.PlayerID = playerID }
 
-	return models.PlayerProjection{
-		PlayerName:  player.Name,
-		TotalPoints: totalPoints,
-	}
+func (player *FantasyProsBatter) Line() Line  { return player.BattingLine().Line }
+func (player *FantasyProsPitcher) Line() Line { return player.PitchingLine().Line }
+
+// CalculateFantasyProsBatterPoints converts FantasyPros batter projections to fantasy points using league settings
+func CalculateFantasyProsBatterPoints(player FantasyProsBatter, settings models.LeagueSettings) (models.PlayerProjection, error) {
+	return Score(player.BattingLine().Line, settings)
+}
+
+// CalculateFantasyProsPitcherPoints converts FantasyPros pitcher projections to fantasy points using league settings
+func CalculateFantasyProsPitcherPoints(player FantasyProsPitcher, settings models.LeagueSettings) (models.PlayerProjection, error) {
+	return Score(player.PitchingLine().Line, settings)
 }
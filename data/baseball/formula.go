@@ -0,0 +1,273 @@
+package baseball
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Formula is a parsed scoring expression over a line's stats, e.g. "1B + 2*2B + 3*3B + 4*HR".
+// It supports numbers, stat names, + - * /, parentheses, unary minus and min(a, b)/max(a, b).
+// A token that reads as a number is one ("2"); anything else is a stat name ("2B", "K%").
+type Formula struct {
+	source string
+	root   node
+	names  []string
+}
+
+type node interface {
+	eval(lookup func(string) (float64, bool)) (float64, bool)
+}
+
+type numberNode float64
+
+type nameNode string
+
+type negateNode struct{ operand node }
+
+type binaryNode struct {
+	op          byte
+	left, right node
+}
+
+type callNode struct {
+	function string
+	args     []node
+}
+
+// ParseFormula parses an expression, reporting the first syntax error
+func ParseFormula(source string) (*Formula, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.expression()
+	if err != nil {
+		return nil, fmt.Errorf("formula %q: %v", source, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("formula %q: unexpected %q", source, p.tokens[p.pos].text)
+	}
+	return &Formula{source: source, root: root, names: p.names}, nil
+}
+
+// Names lists the stat names the formula reads, in order of first use
+func (f *Formula) Names() []string {
+	return f.names
+}
+
+func (f *Formula) String() string {
+	return f.source
+}
+
+// Eval computes the formula, looking stats up by name. ok is false when a stat the formula
+// reads is missing. Division by zero yields 0 (a pitcher with no innings has no K/9).
+func (f *Formula) Eval(lookup func(name string) (float64, bool)) (float64, bool) {
+	return f.root.eval(lookup)
+}
+
+func (n numberNode) eval(func(string) (float64, bool)) (float64, bool) {
+	return float64(n), true
+}
+
+func (n nameNode) eval(lookup func(string) (float64, bool)) (float64, bool) {
+	return lookup(string(n))
+}
+
+func (n negateNode) eval(lookup func(string) (float64, bool)) (float64, bool) {
+	value, ok := n.operand.eval(lookup)
+	return -value, ok
+}
+
+func (n binaryNode) eval(lookup func(string) (float64, bool)) (float64, bool) {
+	left, ok := n.left.eval(lookup)
+	if !ok {
+		return 0, false
+	}
+	right, ok := n.right.eval(lookup)
+	if !ok {
+		return 0, false
+	}
+	switch n.op {
+	case '+':
+		return left + right, true
+	case '-':
+		return left - right, true
+	case '*':
+		return left * right, true
+	default:
+		if right == 0 {
+			return 0, true
+		}
+		return left / right, true
+	}
+}
+
+func (n callNode) eval(lookup func(string) (float64, bool)) (float64, bool) {
+	a, ok := n.args[0].eval(lookup)
+	if !ok {
+		return 0, false
+	}
+	b, ok := n.args[1].eval(lookup)
+	if !ok {
+		return 0, false
+	}
+	if n.function == "min" {
+		return math.Min(a, b), true
+	}
+	return math.Max(a, b), true
+}
+
+type token struct {
+	text   string
+	number bool
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		ch := source[i]
+		switch {
+		case ch == ' ' || ch == '\t':
+			i++
+		case strings.IndexByte("+-*/(),", ch) >= 0:
+			tokens = append(tokens, token{text: string(ch)})
+			i++
+		case isNameByte(ch):
+			start := i
+			for i < len(source) && isNameByte(source[i]) {
+				i++
+			}
+			text := source[start:i]
+			_, err := strconv.ParseFloat(text, 64)
+			tokens = append(tokens, token{text: text, number: err == nil})
+		default:
+			return nil, fmt.Errorf("formula %q: unexpected %q at %d", source, string(ch), i)
+		}
+	}
+	return tokens, nil
+}
+
+func isNameByte(ch byte) bool {
+	return ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch == '_' || ch == '.' || ch == '%'
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	names  []string
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].text
+	}
+	return ""
+}
+
+func (p *parser) expect(text string) error {
+	if p.peek() != text {
+		if p.pos >= len(p.tokens) {
+			return fmt.Errorf("expected %q at end", text)
+		}
+		return fmt.Errorf("expected %q, found %q", text, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// expression = term { ("+" | "-") term }
+func (p *parser) expression() (node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "+" || p.peek() == "-" {
+		op := p.peek()[0]
+		p.pos++
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// term = unary { ("*" | "/") unary }
+func (p *parser) term() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "*" || p.peek() == "/" {
+		op := p.peek()[0]
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// unary = "-" unary | primary
+func (p *parser) unary() (node, error) {
+	if p.peek() == "-" {
+		p.pos++
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{operand: operand}, nil
+	}
+	return p.primary()
+}
+
+// primary = number | name | ("min" | "max") "(" expression "," expression ")" | "(" expression ")"
+func (p *parser) primary() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end")
+	}
+	tok := p.tokens[p.pos]
+	switch {
+	case tok.text == "(":
+		p.pos++
+		inner, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case tok.number:
+		p.pos++
+		value, _ := strconv.ParseFloat(tok.text, 64)
+		return numberNode(value), nil
+	case isNameByte(tok.text[0]):
+		p.pos++
+		function := strings.ToLower(tok.text)
+		if (function == "min" || function == "max") && p.peek() == "(" {
+			p.pos++
+			a, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+			b, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			return callNode{function: function, args: []node{a, b}}, p.expect(")")
+		}
+		if !slices.Contains(p.names, tok.text) {
+			p.names = append(p.names, tok.text)
+		}
+		return nameNode(tok.text), nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
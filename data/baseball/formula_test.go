@@ -0,0 +1,75 @@
+package baseball
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestFormulaEval(t *testing.T) {
+	stats := map[string]float64{"1B": 100, "2B": 30, "3B": 5, "HR": 25, "IP": 180, "SO": 200, "GS": 0}
+	lookup := func(name string) (float64, bool) {
+		value, ok := stats[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		source string
+		want   float64
+		ok     bool
+	}{
+		{source: "1B + 2*2B + 3*3B + 4*HR", want: 100 + 60 + 15 + 100, ok: true},
+		{source: "(1B + 2B) * 2", want: 260, ok: true},
+		{source: "2 + 3 * 4 - 1", want: 13, ok: true},
+		{source: "10 - 4 - 3", want: 3, ok: true},
+		{source: "-HR + 1", want: -24, ok: true},
+		{source: "SO * 9 / IP", want: 10, ok: true},
+		{source: "SO / GS", want: 0, ok: true},
+		{source: "max(0, min(1, HR / 50))", want: 0.5, ok: true},
+		{source: "MAX(HR, 3B)", want: 25, ok: true},
+		{source: "1.5 * HR", want: 37.5, ok: true},
+		{source: "HR + SB", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			formula, err := ParseFormula(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := formula.Eval(lookup)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Eval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormulaNames(t *testing.T) {
+	formula, err := ParseFormula("HR + 2*2B + max(HR, K%) / 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"HR", "2B", "K%"}; !slices.Equal(formula.Names(), want) {
+		t.Errorf("Names = %v, want %v", formula.Names(), want)
+	}
+}
+
+func TestParseFormulaRejects(t *testing.T) {
+	for _, source := range []string{
+		"",
+		"HR +",
+		"(HR + 2B",
+		"HR 2B",
+		"HR ^ 2",
+		"min(HR)",
+		"max(HR, 2B",
+		"HR + )",
+	} {
+		if _, err := ParseFormula(source); err == nil {
+			t.Errorf("ParseFormula(%q) succeeded, want an error", source)
+		}
+	}
+}
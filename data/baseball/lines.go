@@ -0,0 +1,92 @@
+package baseball
+
+import (
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+)
+
+// Line is the normalized stat line every projection source converts into: who the player is
+// plus the stats the source projects. A stat absent from Stats is one the source doesn't
+// provide (FantasyPros has no holds), which is different from a projected zero.
+type Line struct {
+	PlayerID  string
+	IDs       models.PlayerIDs // the source's own IDs for the player, when it exports them
+	Name      string
+	Team      string
+	Positions string // eligible positions as the source wrote them, if any
+	Year      string
+	Source    string
+	Position  string // "batter" or "pitcher"
+	Stats     map[models.Stat]float64
+}
+
+// BattingLine is a normalized batter projection
+type BattingLine struct{ Line }
+
+// PitchingLine is a normalized pitcher projection
+type PitchingLine struct{ Line }
+
+// Has reports whether the source projects stat
+func (l Line) Has(stat models.Stat) bool {
+	_, ok := l.Stats[stat]
+	return ok
+}
+
+// Get returns a projected stat, 0 when missing
+func (l Line) Get(stat models.Stat) float64 {
+	return l.Stats[stat]
+}
+
+// Missing lists the stats of the line's catalog (batting or pitching) the source doesn't provide
+func (l Line) Missing() []models.Stat {
+	catalog := models.BattingStats
+	if l.Position == "pitcher" {
+		catalog = models.PitchingStats
+	}
+	var missing []models.Stat
+	for _, stat := range catalog {
+		if !l.Has(stat) {
+			missing = append(missing, stat)
+		}
+	}
+	return missing
+}
+
+// projected removes the stats a row marks as not projected. Outs go with innings, since
+// one is stored as the other.
+func projected(stats map[models.Stat]float64, unprojected []models.Stat) map[models.Stat]float64 {
+	for _, stat := range unprojected {
+		delete(stats, stat)
+		if stat == models.StatIP {
+			delete(stats, models.StatOuts)
+		}
+	}
+	return stats
+}
+
+// NewBattingLine builds a batting line from a source's stats and fills in the stats that
+// can be derived from them: singles from hits, total bases from the hit types.
+func NewBattingLine(line Line) BattingLine {
+	line.Position = "batter"
+	s := line.Stats
+	if !line.Has(models.Stat1B) && line.Has(models.StatH) && line.Has(models.Stat2B) && line.Has(models.Stat3B) && line.Has(models.StatHR) {
+		s[models.Stat1B] = s[models.StatH] - s[models.Stat2B] - s[models.Stat3B] - s[models.StatHR]
+	}
+	if !line.Has(models.StatTB) && line.Has(models.Stat1B) && line.Has(models.Stat2B) && line.Has(models.Stat3B) && line.Has(models.StatHR) {
+		s[models.StatTB] = s[models.Stat1B] + 2*s[models.Stat2B] + 3*s[models.Stat3B] + 4*s[models.StatHR]
+	}
+	return BattingLine{line}
+}
+
+// NewPitchingLine builds a pitching line from a source's stats, keeping innings and outs in step
+func NewPitchingLine(line Line) PitchingLine {
+	line.Position = "pitcher"
+	s := line.Stats
+	switch {
+	case line.Has(models.StatOuts) && !line.Has(models.StatIP):
+		s[models.StatIP] = s[models.StatOuts] / 3
+	case line.Has(models.StatIP) && !line.Has(models.StatOuts):
+		s[models.StatOuts] = float64(utils.OutsFromInnings(s[models.StatIP]))
+	}
+	return PitchingLine{line}
+}
@@ -0,0 +1,78 @@
+package baseball
+
+import (
+	"slices"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+func TestNewBattingLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats map[models.Stat]float64
+		want  map[models.Stat]float64
+	}{
+		{
+			name:  "singles and total bases from hits",
+			stats: map[models.Stat]float64{models.StatH: 150, models.Stat2B: 30, models.Stat3B: 2, models.StatHR: 40},
+			want:  map[models.Stat]float64{models.Stat1B: 78, models.StatTB: 78 + 60 + 6 + 160},
+		},
+		{
+			name:  "projected stats are kept",
+			stats: map[models.Stat]float64{models.StatH: 150, models.Stat1B: 70, models.Stat2B: 30, models.Stat3B: 2, models.StatHR: 40, models.StatTB: 300},
+			want:  map[models.Stat]float64{models.Stat1B: 70, models.StatTB: 300},
+		},
+		{
+			name:  "total bases from singles without hits",
+			stats: map[models.Stat]float64{models.Stat1B: 100, models.Stat2B: 20, models.Stat3B: 0, models.StatHR: 10},
+			want:  map[models.Stat]float64{models.StatTB: 180},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := NewBattingLine(Line{Stats: tt.stats})
+			if line.Position != "batter" {
+				t.Errorf("Position = %q", line.Position)
+			}
+			for stat, want := range tt.want {
+				if got := line.Get(stat); got != want {
+					t.Errorf("%s = %v, want %v", stat, got, want)
+				}
+			}
+		})
+	}
+
+	// Without every hit type there's nothing to derive
+	line := NewBattingLine(Line{Stats: map[models.Stat]float64{models.StatH: 150, models.StatHR: 40}})
+	if line.Has(models.Stat1B) || line.Has(models.StatTB) {
+		t.Errorf("derived stats from a partial line: %v", line.Stats)
+	}
+}
+
+func TestNewPitchingLine(t *testing.T) {
+	fromInnings := NewPitchingLine(Line{Stats: map[models.Stat]float64{models.StatIP: 173 + 1.0/3}})
+	if fromInnings.Position != "pitcher" || fromInnings.Get(models.StatOuts) != 520 {
+		t.Errorf("outs from innings = %v", fromInnings.Get(models.StatOuts))
+	}
+	fromOuts := NewPitchingLine(Line{Stats: map[models.Stat]float64{models.StatOuts: 600}})
+	if fromOuts.Get(models.StatIP) != 200 {
+		t.Errorf("innings from outs = %v", fromOuts.Get(models.StatIP))
+	}
+}
+
+func TestLineMissing(t *testing.T) {
+	stats := make(map[models.Stat]float64)
+	for _, stat := range models.PitchingStats {
+		if stat != models.StatHLD && stat != models.StatBS {
+			stats[stat] = 0
+		}
+	}
+	line := Line{Position: "pitcher", Stats: stats}
+	if want := []models.Stat{models.StatHLD, models.StatBS}; !slices.Equal(line.Missing(), want) {
+		t.Errorf("Missing = %v, want %v", line.Missing(), want)
+	}
+	if !line.Has(models.StatSV) || line.Has(models.StatHLD) {
+		t.Error("Has disagrees with the stats")
+	}
+}
@@ -0,0 +1,511 @@
+package baseball
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"super-fantasy-api/models"
+)
+
+// BenchLabel is the slot players past a team's starting lineup fill in a mock draft
+const BenchLabel = "BN"
+
+// MockPlayer is a player in a mock draft's pool
+type MockPlayer struct {
+	RosterPlayer
+	ID      string // shared by a two-way player's entries, which are drafted together
+	Name    string
+	ADP     float64 // average draft position, 0 when unknown
+	Dollars float64 // pre-draft auction price
+
+	adpDollars float64 // the price of the player whose value ranks where this player's ADP does
+}
+
+// MockCandidate is a player a team has room for, and the slot they'd fill (BN for the bench)
+type MockCandidate struct {
+	MockPlayer
+	Slot string
+}
+
+// BotStrategy decides what a mock draft bot takes. Score ranks the players a team has room
+// for: the bot drafts (or nominates) the highest. Bid is the most the bot would pay for a
+// player at auction. Noise is applied on top of both.
+type BotStrategy interface {
+	Score(candidate MockCandidate, team *MockTeam) float64
+	Bid(candidate MockCandidate, team *MockTeam) float64
+}
+
+// botStrategies are the strategies bots can be given, by name
+var botStrategies = map[string]BotStrategy{
+	models.StrategyADP:    adpStrategy{},
+	models.StrategyPoints: pointsStrategy{},
+	models.StrategyNeed:   needStrategy{},
+}
+
+// adpStrategy drafts the way the market does: lowest ADP first, paying what a player drafted
+// there is worth
+type adpStrategy struct{}
+
+func (adpStrategy) Score(candidate MockCandidate, _ *MockTeam) float64 { return -candidate.ADP }
+func (adpStrategy) Bid(candidate MockCandidate, _ *MockTeam) float64   { return candidate.adpDollars }
+
+// pointsStrategy drafts by projected value and pays a player's auction price
+type pointsStrategy struct{}
+
+func (pointsStrategy) Score(candidate MockCandidate, _ *MockTeam) float64 { return candidate.Value }
+func (pointsStrategy) Bid(candidate MockCandidate, _ *MockTeam) float64   { return candidate.Dollars }
+
+// needStrategy drafts by projected value, but fills its starting lineup first: players who'd
+// only sit on its bench count for a quarter, and are bid on at half price
+type needStrategy struct{}
+
+func (needStrategy) Score(candidate MockCandidate, _ *MockTeam) float64 {
+	if candidate.Slot == BenchLabel {
+		return candidate.Value / 4
+	}
+	return candidate.Value
+}
+
+func (needStrategy) Bid(candidate MockCandidate, _ *MockTeam) float64 {
+	if candidate.Slot == BenchLabel {
+		return candidate.Dollars / 2
+	}
+	return candidate.Dollars
+}
+
+// MockTeam is a team in a mock draft: its strategy (none for the caller's team), its picks and
+// the roster spots and budget it has left
+type MockTeam struct {
+	Seat     int
+	Strategy string
+	Picks    []MockDraftPick
+	Spent    float64
+
+	strategy BotStrategy
+	noise    float64
+	draft    *MockDraft
+	open     []int // open spots per starting slot
+	bench    int
+	stuck    bool // no available player fits the team's open spots
+}
+
+// MockDraftPick is one player taken in a mock draft: the team's seat, the player's index in
+// the pool and the slot they fill
+type MockDraftPick struct {
+	Number int
+	Team   int
+	Player int
+	Slot   string
+	Price  float64
+}
+
+// MockSelection is a player the caller's team drafts, by index in the pool; in an auction,
+// Price is the most the caller will bid
+type MockSelection struct {
+	Player int
+	Price  float64
+}
+
+// MockDraftOutcome is how a mock draft went. Complete is false when it stopped for the caller's
+// next pick.
+type MockDraftOutcome struct {
+	Picks    []MockDraftPick
+	Teams    []*MockTeam
+	Complete bool
+}
+
+// MockDraft runs snake or auction drafts of a player pool between bots and the caller's team.
+// NewMockDraft checks the settings once.
+type MockDraft struct {
+	settings models.MockDraftSettings
+	terms    models.AuctionSettings
+	players  []MockPlayer
+	slots    []leagueSlot // each team's starting slots
+	bench    int          // each team's bench spots
+	accepts  [][]bool     // whether each player fits each starting slot
+}
+
+// NewMockDraft validates the mock draft settings against the league's terms (auction settings
+// with defaults filled in) and prepares the pool. Players without an ADP are placed after
+// those with one, by value.
+func NewMockDraft(settings models.MockDraftSettings, terms models.AuctionSettings, players []MockPlayer) (*MockDraft, error) {
+	switch settings.Type {
+	case "":
+		settings.Type = models.DraftSnake
+	case models.DraftSnake, models.DraftAuction:
+	default:
+		return nil, fmt.Errorf("type must be '%s' or '%s'", models.DraftSnake, models.DraftAuction)
+	}
+	if settings.Slot < 0 || settings.Slot > terms.Teams {
+		return nil, fmt.Errorf("slot must be between 1 and %d", terms.Teams)
+	}
+	if settings.Slot == 0 {
+		settings.Slot = 1
+	}
+	if settings.Strategy == "" {
+		settings.Strategy = models.StrategyADP
+	}
+	if len(settings.Bots) > terms.Teams-1 {
+		return nil, fmt.Errorf("%d teams have only %d bots", terms.Teams, terms.Teams-1)
+	}
+	for _, bot := range append([]models.BotSettings{{Strategy: settings.Strategy, Noise: settings.Noise}}, settings.Bots...) {
+		if _, ok := botStrategies[bot.Strategy]; bot.Strategy != "" && !ok {
+			return nil, fmt.Errorf("strategy must be '%s', '%s' or '%s'", models.StrategyADP, models.StrategyPoints, models.StrategyNeed)
+		}
+		if bot.Noise < 0 {
+			return nil, fmt.Errorf("noise can't be negative")
+		}
+	}
+
+	draft := &MockDraft{settings: settings, terms: terms, players: append([]MockPlayer(nil), players...)}
+	for _, slot := range terms.Roster {
+		if models.BenchSlot(slot.Position) {
+			draft.bench += slot.Count
+			continue
+		}
+		draft.slots = append(draft.slots, leagueSlot{name: strings.ToUpper(strings.TrimSpace(slot.Position)), spots: slot.Count})
+	}
+	draft.accepts = make([][]bool, len(draft.players))
+	for i, player := range draft.players {
+		draft.accepts[i] = make([]bool, len(draft.slots))
+		for j, slot := range draft.slots {
+			draft.accepts[i][j] = models.SlotAccepts(slot.name, player.Position, player.Eligible)
+		}
+	}
+	draft.rankADP()
+	return draft, nil
+}
+
+// rankADP fills in missing ADPs after the known ones, by value, and gives each player the
+// price of the player whose value ranks where their ADP does
+func (m *MockDraft) rankADP() {
+	adps := make([]float64, len(m.players))
+	values := make([]float64, len(m.players))
+	for i, player := range m.players {
+		adps[i], values[i] = player.ADP, player.Value
+	}
+	fillADP(adps, values)
+	for i := range m.players {
+		m.players[i].ADP = adps[i]
+	}
+
+	byADP := make([]int, len(m.players))
+	dollars := make([]float64, len(m.players))
+	for i, player := range m.players {
+		byADP[i] = i
+		dollars[i] = player.Dollars
+	}
+	sort.SliceStable(byADP, func(a, b int) bool { return m.players[byADP[a]].ADP < m.players[byADP[b]].ADP })
+	sort.Sort(sort.Reverse(sort.Float64Slice(dollars)))
+	for rank, i := range byADP {
+		m.players[i].adpDollars = dollars[rank]
+	}
+}
+
+// fillADP gives the players without an ADP (zero) the picks after the last known one, by value
+func fillADP(adps, values []float64) {
+	var known float64
+	var missing []int
+	for i, adp := range adps {
+		known = math.Max(known, adp)
+		if adp == 0 {
+			missing = append(missing, i)
+		}
+	}
+	sort.SliceStable(missing, func(a, b int) bool { return values[missing[a]] > values[missing[b]] })
+	for rank, i := range missing {
+		adps[i] = known + float64(rank+1)
+	}
+}
+
+// Players is the pool, with ADPs filled in
+func (m *MockDraft) Players() []MockPlayer {
+	return m.players
+}
+
+// Caller is the seat of the caller's team, from 0
+func (m *MockDraft) Caller() int {
+	return m.settings.Slot - 1
+}
+
+// Run drafts until every roster is full, or until it's the caller's turn and their picks have
+// run out (without autopick). A snake draft refuses a caller's pick that's already taken or
+// that their roster has no room for; at auction, the caller bids up to their price whenever a
+// player they picked comes up, and nominates the next one they haven't lost yet. The same
+// settings and seed always give the same draft.
+func (m *MockDraft) Run(picks []MockSelection) (MockDraftOutcome, error) {
+	rng := rand.New(rand.NewSource(m.settings.Seed))
+	outcome := MockDraftOutcome{Teams: make([]*MockTeam, m.terms.Teams)}
+	for seat := range outcome.Teams {
+		team := &MockTeam{Seat: seat, draft: m, open: make([]int, len(m.slots)), bench: m.bench}
+		for j, slot := range m.slots {
+			team.open[j] = slot.spots
+		}
+		if seat != m.Caller() {
+			bot := models.BotSettings{Strategy: m.settings.Strategy, Noise: m.settings.Noise}
+			index := seat // bots are listed in seat order, skipping the caller
+			if seat > m.Caller() {
+				index--
+			}
+			if index < len(m.settings.Bots) {
+				if m.settings.Bots[index].Strategy != "" {
+					bot.Strategy = m.settings.Bots[index].Strategy
+				}
+				if m.settings.Bots[index].Noise != 0 {
+					bot.Noise = m.settings.Bots[index].Noise
+				}
+			}
+			team.Strategy, team.strategy, team.noise = bot.Strategy, botStrategies[bot.Strategy], bot.Noise
+		}
+		outcome.Teams[seat] = team
+	}
+	for _, pick := range picks {
+		if pick.Player < 0 || pick.Player >= len(m.players) {
+			return outcome, fmt.Errorf("unknown player")
+		}
+		if m.settings.Type == models.DraftAuction && pick.Price < m.terms.MinBid {
+			return outcome, fmt.Errorf("the price for %s must be at least the $%g minimum bid", m.players[pick.Player].Name, m.terms.MinBid)
+		}
+	}
+
+	taken := make([]bool, len(m.players))
+	take := func(team *MockTeam, player int, price float64) {
+		slot := team.fill(player)
+		id := m.players[player].ID
+		for i := range m.players {
+			// a two-way player's batting and pitching entries are drafted together
+			if i == player || (id != "" && m.players[i].ID == id) {
+				taken[i] = true
+			}
+		}
+		team.Spent += price
+		pick := MockDraftPick{Number: len(outcome.Picks) + 1, Team: team.Seat, Player: player, Slot: slot, Price: price}
+		team.Picks = append(team.Picks, pick)
+		outcome.Picks = append(outcome.Picks, pick)
+	}
+	choose := func(team *MockTeam, strategy BotStrategy, noise float64) int {
+		best, bestScore := -1, math.Inf(-1)
+		for i := range m.players {
+			if taken[i] || team.slotIndex(i) == -2 {
+				continue
+			}
+			score := strategy.Score(team.candidate(i), team)
+			if noise > 0 {
+				score *= 1 + noise*rng.NormFloat64()
+			}
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		return best
+	}
+
+	if m.settings.Type == models.DraftSnake {
+		next := 0
+		for number := 0; number < m.terms.Teams*m.spots(); number++ {
+			team := outcome.Teams[SnakeTeam(m.terms.Teams, number)]
+			player := -1
+			switch {
+			case team.Seat != m.Caller():
+				player = choose(team, team.strategy, team.noise)
+			case next < len(picks):
+				player = picks[next].Player
+				next++
+				if taken[player] {
+					return outcome, fmt.Errorf("%s was taken before your pick %d", m.players[player].Name, number+1)
+				}
+				if team.slotIndex(player) == -2 {
+					return outcome, fmt.Errorf("your roster has no room for %s at pick %d", m.players[player].Name, number+1)
+				}
+			case m.settings.Autopick:
+				player = choose(team, pointsStrategy{}, 0)
+			default:
+				return outcome, nil
+			}
+			if player >= 0 {
+				take(team, player, 0)
+			}
+		}
+		outcome.Complete = true
+		return outcome, nil
+	}
+
+	// At auction, nominations go round the table; every team with room bids
+	wanted := make(map[int]float64)
+	for _, pick := range picks {
+		wanted[pick.Player] = pick.Price
+	}
+	next, nominator := 0, 0
+	for {
+		seat := -1
+		for k := 0; k < m.terms.Teams; k++ {
+			if candidate := (nominator + k) % m.terms.Teams; outcome.Teams[candidate].Open() > 0 && !outcome.Teams[candidate].stuck {
+				seat = candidate
+				break
+			}
+		}
+		if seat < 0 {
+			break
+		}
+		nominator = seat + 1
+		team := outcome.Teams[seat]
+
+		player := -1
+		if seat == m.Caller() {
+			for next < len(picks) && taken[picks[next].Player] {
+				next++
+			}
+			switch {
+			case next < len(picks):
+				player = picks[next].Player
+				if team.slotIndex(player) == -2 {
+					return outcome, fmt.Errorf("your roster has no room for %s", m.players[player].Name)
+				}
+			case m.settings.Autopick:
+				player = choose(team, pointsStrategy{}, 0)
+			default:
+				return outcome, nil
+			}
+		} else {
+			player = choose(team, team.strategy, team.noise)
+		}
+		if player < 0 {
+			team.stuck = true
+			continue
+		}
+
+		inflation := m.inflation(outcome.Teams, taken)
+		winner, high, second := -1, 0.0, 0.0
+		for k := 0; k < m.terms.Teams; k++ {
+			bidder := outcome.Teams[(seat+k)%m.terms.Teams]
+			if bidder.Open() == 0 || bidder.slotIndex(player) == -2 {
+				continue
+			}
+			var bid float64
+			if bidder.Seat == m.Caller() {
+				if price, ok := wanted[player]; ok {
+					bid = price
+				} else if m.settings.Autopick && next >= len(picks) {
+					bid = m.players[player].Dollars
+				}
+			} else {
+				bid = bidder.strategy.Bid(bidder.candidate(player), bidder)
+				if bid > m.terms.MinBid {
+					bid = m.terms.MinBid + (bid-m.terms.MinBid)*inflation
+				}
+				if bidder.noise > 0 {
+					bid *= 1 + bidder.noise*rng.NormFloat64()
+				}
+			}
+			bid = math.Min(math.Floor(bid), bidder.MaxBid())
+			if bidder.Seat == seat {
+				bid = math.Max(bid, m.terms.MinBid) // the nominator opens the bidding
+			}
+			if bid < m.terms.MinBid {
+				continue
+			}
+			if bid > high {
+				winner, high, second = bidder.Seat, bid, high
+			} else if bid > second {
+				second = bid
+			}
+		}
+		take(outcome.Teams[winner], player, math.Min(high, math.Max(m.terms.MinBid, second+1)))
+	}
+	outcome.Complete = true
+	return outcome, nil
+}
+
+// inflation is how far the money left to spend over the minimum bids outruns the pre-draft
+// prices of the players left, over the minimum: bots scale their bids by it, so the league's
+// budget gets spent as it would in a real auction
+func (m *MockDraft) inflation(teams []*MockTeam, taken []bool) float64 {
+	var money, value float64
+	for _, team := range teams {
+		money += m.terms.Budget - team.Spent - m.terms.MinBid*float64(team.Open())
+	}
+	for i, player := range m.players {
+		if !taken[i] {
+			value += math.Max(0, player.Dollars-m.terms.MinBid)
+		}
+	}
+	if value <= 0 {
+		return 1
+	}
+	return money / value
+}
+
+// spots is each team's roster size
+func (m *MockDraft) spots() int {
+	spots := m.bench
+	for _, slot := range m.slots {
+		spots += slot.spots
+	}
+	return spots
+}
+
+// Open is how many roster spots the team has left
+func (t *MockTeam) Open() int {
+	open := t.bench
+	for _, count := range t.open {
+		open += count
+	}
+	return open
+}
+
+// MaxBid is the most the team can bid and still fill its roster at the minimum bid
+func (t *MockTeam) MaxBid() float64 {
+	if t.Open() == 0 {
+		return 0
+	}
+	return t.draft.terms.Budget - t.Spent - t.draft.terms.MinBid*float64(t.Open()-1)
+}
+
+// candidate pairs player i with the slot they'd fill on the team
+func (t *MockTeam) candidate(i int) MockCandidate {
+	candidate := MockCandidate{MockPlayer: t.draft.players[i], Slot: BenchLabel}
+	if j := t.slotIndex(i); j >= 0 {
+		candidate.Slot = t.draft.slots[j].name
+	}
+	return candidate
+}
+
+// slotIndex is the open starting slot player i would fill: the least flexible one that
+// accepts them, so a shortstop fills SS before MI or UTIL. -1 means the bench and -2 no room.
+func (t *MockTeam) slotIndex(i int) int {
+	best := -1
+	for j, slot := range t.draft.slots {
+		if t.open[j] > 0 && t.draft.accepts[i][j] && (best < 0 || slotFlexibility(slot.name) < slotFlexibility(t.draft.slots[best].name)) {
+			best = j
+		}
+	}
+	if best < 0 && t.bench == 0 {
+		return -2
+	}
+	return best
+}
+
+// fill puts player i in their slot, returning its name
+func (t *MockTeam) fill(i int) string {
+	j := t.slotIndex(i)
+	if j < 0 {
+		t.bench--
+		return BenchLabel
+	}
+	t.open[j]--
+	return t.draft.slots[j].name
+}
+
+// slotFlexibility ranks slots by how many kinds of player they take
+func slotFlexibility(slot string) int {
+	switch slot {
+	case "UTIL", "DH", "P":
+		return 2
+	case "CI", "MI":
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,161 @@
+package baseball
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+// mockLeague is a four-team league with a small roster, and a pool of players to draft with
+// values, prices and ADPs that don't quite agree
+func mockLeague(t *testing.T) (models.AuctionSettings, []MockPlayer) {
+	t.Helper()
+	auction, err := NewAuction(models.AuctionSettings{
+		Teams:  4,
+		Budget: 100,
+		Roster: []models.RosterSlot{{Position: "C", Count: 1}, {Position: "SS", Count: 1}, {Position: "OF", Count: 2}, {Position: "UTIL", Count: 1}, {Position: "SP", Count: 2}, {Position: "BN", Count: 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	positions := [][]string{{"C"}, {"SS"}, {"OF"}, {"OF"}, {"SS", "OF"}, {"SP"}, {"SP"}, {}}
+	var players []MockPlayer
+	for i := 0; i < 64; i++ {
+		eligible := positions[i%len(positions)]
+		role := "batter"
+		if len(eligible) == 1 && eligible[0] == "SP" {
+			role = "pitcher"
+		}
+		value := float64(400 - 5*i)
+		players = append(players, MockPlayer{
+			RosterPlayer: RosterPlayer{Position: role, Eligible: eligible, Value: value},
+			ID:           fmt.Sprintf("p%d", i),
+			Name:         fmt.Sprintf("Player %d", i),
+			ADP:          float64(i + 1 + (i%5)*2),
+			Dollars:      max(1, value/10-8),
+		})
+	}
+	return auction.Settings(), players
+}
+
+func TestMockDraftSeedReproducible(t *testing.T) {
+	terms, players := mockLeague(t)
+	for _, draftType := range []string{models.DraftSnake, models.DraftAuction} {
+		for _, strategy := range []string{models.StrategyADP, models.StrategyPoints, models.StrategyNeed} {
+			t.Run(draftType+"/"+strategy, func(t *testing.T) {
+				settings := models.MockDraftSettings{Type: draftType, Slot: 2, Strategy: strategy, Noise: 0.3, Seed: 11, Autopick: true}
+				run := func(seed int64) MockDraftOutcome {
+					settings.Seed = seed
+					draft, err := NewMockDraft(settings, terms, players)
+					if err != nil {
+						t.Fatal(err)
+					}
+					outcome, err := draft.Run(nil)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !outcome.Complete {
+						t.Fatal("draft didn't complete")
+					}
+					return outcome
+				}
+
+				first, second := run(11), run(11)
+				if !reflect.DeepEqual(first.Picks, second.Picks) {
+					t.Fatal("the same seed gave different drafts")
+				}
+				for seat := range first.Teams {
+					if first.Teams[seat].Spent != second.Teams[seat].Spent {
+						t.Errorf("seat %d spent %v, then %v", seat, first.Teams[seat].Spent, second.Teams[seat].Spent)
+					}
+				}
+				if len(first.Picks) != terms.Teams*9 {
+					t.Errorf("%d picks, want %d", len(first.Picks), terms.Teams*9)
+				}
+
+				differs := false
+				for seed := int64(1); seed <= 5 && !differs; seed++ {
+					differs = !reflect.DeepEqual(run(seed).Picks, first.Picks)
+				}
+				if !differs {
+					t.Error("noise had no effect across seeds")
+				}
+			})
+		}
+	}
+}
+
+func TestMockDraftBots(t *testing.T) {
+	terms, players := mockLeague(t)
+	settings := models.MockDraftSettings{
+		Slot:     4,
+		Autopick: true,
+		Bots:     []models.BotSettings{{Strategy: models.StrategyPoints}, {Strategy: models.StrategyNeed}},
+	}
+	draft, err := NewMockDraft(settings, terms, players)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcome, err := draft.Run(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	strategies := []string{models.StrategyPoints, models.StrategyNeed, models.StrategyADP, ""}
+	for seat, team := range outcome.Teams {
+		if team.Strategy != strategies[seat] {
+			t.Errorf("seat %d strategy = %q, want %q", seat, team.Strategy, strategies[seat])
+		}
+	}
+	// Without noise the first round goes by each bot's strategy: the ADP bot passes over more
+	// valuable players for the lowest ADP left
+	first := outcome.Picks[:3]
+	if first[0].Player != 0 || first[1].Player != 1 {
+		t.Errorf("points and need bots took %d and %d, want 0 and 1", first[0].Player, first[1].Player)
+	}
+	if first[2].Player != 5 {
+		t.Errorf("ADP bot took %d (ADP %v), want 5 (ADP 6)", first[2].Player, players[first[2].Player].ADP)
+	}
+}
+
+func TestMockDraftSharedIDs(t *testing.T) {
+	terms, players := mockLeague(t)
+	// A two-way player's entries share an ID and go together; players without an ID don't
+	players[2].ID, players[3].ID = "two-way", "two-way"
+	players[0].ID, players[1].ID = "", ""
+	draft, err := NewMockDraft(models.MockDraftSettings{Strategy: models.StrategyPoints, Autopick: true}, terms, players)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcome, err := draft.Run([]MockSelection{{Player: 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	taken := make(map[int]int)
+	for _, pick := range outcome.Picks {
+		taken[pick.Player]++
+	}
+	if taken[0] != 1 || taken[1] != 1 {
+		t.Errorf("players without an ID were taken %d and %d times, want once each", taken[0], taken[1])
+	}
+	if taken[2] != 1 || taken[3] != 0 {
+		t.Errorf("two-way entries were taken %d and %d times, want 1 and 0", taken[2], taken[3])
+	}
+}
+
+func TestNewMockDraftRejects(t *testing.T) {
+	terms, players := mockLeague(t)
+	for _, settings := range []models.MockDraftSettings{
+		{Type: "keeper"},
+		{Slot: 5},
+		{Strategy: "random"},
+		{Noise: -1},
+		{Bots: make([]models.BotSettings, 4)},
+	} {
+		if _, err := NewMockDraft(settings, terms, players); err == nil {
+			t.Errorf("NewMockDraft(%+v) succeeded, want an error", settings)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package baseball
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"super-fantasy-api/models"
+)
+
+// Optimizer limits
+const (
+	// maxOptimizerNodes stops an auction search that runs this long with the best lineup found
+	maxOptimizerNodes = 5_000_000
+	// maxBudgetSteps is how finely a budget is searched: prices are whole dollars, or coarser
+	// steps for budgets over this many dollars
+	maxBudgetSteps = 1000
+)
+
+// OptimizerPlayer is a player the optimizer can start: their role, eligibility and value,
+// their auction price and their ADP (0 when unknown)
+type OptimizerPlayer struct {
+	RosterPlayer
+	Dollars float64
+	ADP     float64
+}
+
+// OptimizerSpot is a starting spot of the optimal lineup
+type OptimizerSpot struct {
+	Slot   string
+	Player int     // index in the pool, -1 when no player fills the spot
+	Price  float64 // what the player costs at auction, in whole dollars
+	Pick   int     // the pick the player is taken at in a snake draft
+}
+
+// OptimizerResult is the optimal lineup, in the roster's slot order
+type OptimizerResult struct {
+	Spots     []OptimizerSpot
+	Projected float64 // the starters' total value
+	Spent     float64 // at auction, the starters' prices plus the minimum bid for every other spot
+	Optimal   bool    // false when the auction search stopped at its limit
+}
+
+// Optimizer finds the starting lineup with the most projected value one team can build,
+// within an auction budget or from its picks in a snake draft. NewOptimizer checks the
+// settings once.
+type Optimizer struct {
+	settings models.OptimizeSettings
+	terms    models.AuctionSettings
+	roster   *Roster // one team's roster
+}
+
+// NewOptimizer validates the settings against the league's terms (auction settings with
+// defaults filled in). A slot is turned into the team's snake picks, one per roster spot.
+func NewOptimizer(settings models.OptimizeSettings, terms models.AuctionSettings) (*Optimizer, error) {
+	roster, err := NewRoster(1, terms.Roster)
+	if err != nil {
+		return nil, err
+	}
+	spots := roster.Starters() + roster.Bench()
+
+	if settings.Budget < 0 {
+		return nil, fmt.Errorf("budget can't be negative")
+	}
+	if settings.Slot < 0 || settings.Slot > terms.Teams {
+		return nil, fmt.Errorf("slot must be between 1 and %d", terms.Teams)
+	}
+	if settings.Slot > 0 && len(settings.Picks) > 0 {
+		return nil, fmt.Errorf("set picks or slot, not both")
+	}
+	picks := slices.Clone(settings.Picks)
+	if settings.Slot > 0 {
+		for pick := 0; pick < spots*terms.Teams; pick++ {
+			if SnakeTeam(terms.Teams, pick) == settings.Slot-1 {
+				picks = append(picks, pick+1)
+			}
+		}
+	}
+	slices.Sort(picks)
+	for i, pick := range picks {
+		if pick <= 0 {
+			return nil, fmt.Errorf("picks must be positive")
+		}
+		if i > 0 && picks[i-1] == pick {
+			return nil, fmt.Errorf("pick %d is listed twice", pick)
+		}
+	}
+	settings.Picks = picks
+
+	if settings.Budget == 0 {
+		settings.Budget = terms.Budget
+	}
+	if len(picks) == 0 && terms.MinBid*float64(spots) > settings.Budget {
+		return nil, fmt.Errorf("a $%g budget can't fill %d roster spots at the $%g minimum bid", settings.Budget, spots, terms.MinBid)
+	}
+	return &Optimizer{settings: settings, terms: terms, roster: roster}, nil
+}
+
+// Settings returns the settings with the picks and budget filled in
+func (o *Optimizer) Settings() models.OptimizeSettings {
+	return o.settings
+}
+
+// Solve finds the optimal lineup from a pool of players. A player's value only counts when
+// they start, so the bench is left to the minimum bid (or the picks the starters don't use).
+func (o *Optimizer) Solve(players []OptimizerPlayer) OptimizerResult {
+	if len(o.settings.Picks) > 0 {
+		return o.snake(players)
+	}
+	return o.auction(players)
+}
+
+// optimizerGroup is one starting slot's spots and the players that can fill them, best first
+type optimizerGroup struct {
+	slot       leagueSlot
+	candidates []int
+}
+
+// auction searches for the lineup with the most value whose prices fit the budget.
+//
+// Every roster spot costs at least the minimum bid, so what's searched is how to spend the
+// rest. Players priced higher than another player who's worth at least as much and fits the
+// same slots are dropped once there are enough of the latter to fill those slots. The search
+// is a branch and bound over each slot's candidates, bounded by a dynamic program over slots
+// and budget that's exact except that it lets a player start at two slots (SS at SS and MI):
+// when the best lineup it allows doesn't do that, it's taken at once.
+func (o *Optimizer) auction(players []OptimizerPlayer) OptimizerResult {
+	step := math.Max(1, math.Ceil(o.settings.Budget/maxBudgetSteps))
+	minBid := math.Ceil(o.terms.MinBid)
+	spots := o.roster.Starters() + o.roster.Bench()
+	spare := int(math.Floor((o.settings.Budget - minBid*float64(spots)) / step))
+
+	// extra[i] is what a player costs over the minimum bid, in budget steps
+	price := func(i int) float64 { return math.Max(minBid, math.Round(players[i].Dollars)) }
+	extra := make([]int, len(players))
+	for i := range players {
+		extra[i] = int(math.Ceil((price(i) - minBid) / step))
+	}
+
+	groups := o.groups(players, func(i int) bool { return extra[i] <= spare })
+	// Signatures are the slots a player fits; within one, drop the dominated players
+	signatures := make(map[string][]int)
+	for i := range players {
+		var signature []byte
+		for _, group := range groups {
+			if slices.Contains(group.candidates, i) {
+				signature = append(signature, '1')
+			} else {
+				signature = append(signature, '0')
+			}
+		}
+		if slices.Contains(signature, '1') {
+			signatures[string(signature)] = append(signatures[string(signature)], i)
+		}
+	}
+	keep := make(map[int]bool)
+	for signature, members := range signatures {
+		var capacity int
+		for g, fits := range signature {
+			if fits == '1' {
+				capacity += groups[g].slot.spots
+			}
+		}
+		sort.SliceStable(members, func(a, b int) bool {
+			if extra[members[a]] != extra[members[b]] {
+				return extra[members[a]] < extra[members[b]]
+			}
+			return players[members[a]].Value > players[members[b]].Value
+		})
+		for k, i := range members {
+			better := 0
+			for _, j := range members[:k] {
+				if players[j].Value >= players[i].Value {
+					better++
+				}
+			}
+			if better < capacity {
+				keep[i] = true
+			}
+		}
+	}
+	for g := range groups {
+		groups[g].candidates = slices.DeleteFunc(groups[g].candidates, func(i int) bool { return !keep[i] })
+	}
+	// Scarce slots first, so the search commits where there's least choice
+	sort.SliceStable(groups, func(a, b int) bool { return len(groups[a].candidates) < len(groups[b].candidates) })
+
+	// bounds[g] holds, by candidate, spots left in the group and budget steps left, the most
+	// value the rest of the lineup can add from that point
+	budgets := spare + 1
+	bounds := make([][]float64, len(groups)+1)
+	bounds[len(groups)] = make([]float64, budgets)
+	spotsOf := func(g int) int {
+		if g == len(groups) {
+			return 0
+		}
+		return groups[g].slot.spots
+	}
+	at := func(g, from, left, b int) int {
+		if g == len(groups) {
+			return b
+		}
+		return (from*(groups[g].slot.spots+1)+left)*budgets + b
+	}
+	for g := len(groups) - 1; g >= 0; g-- {
+		candidates, size := groups[g].candidates, groups[g].slot.spots
+		bounds[g] = make([]float64, (len(candidates)+1)*(size+1)*budgets)
+		next := func(b int) float64 { return bounds[g+1][at(g+1, 0, spotsOf(g+1), b)] }
+		for left := 0; left <= size; left++ {
+			for b := 0; b < budgets; b++ {
+				bounds[g][at(g, len(candidates), left, b)] = next(b)
+			}
+		}
+		for from := len(candidates) - 1; from >= 0; from-- {
+			i := candidates[from]
+			for left := 0; left <= size; left++ {
+				for b := 0; b < budgets; b++ {
+					best := bounds[g][at(g, from+1, left, b)]
+					if left == 0 {
+						best = next(b)
+					} else if extra[i] <= b {
+						best = math.Max(best, players[i].Value+bounds[g][at(g, from+1, left-1, b-extra[i])])
+					}
+					bounds[g][at(g, from, left, b)] = best
+				}
+			}
+		}
+	}
+
+	var (
+		nodes     int
+		used      = make([]bool, len(players))
+		chosen    = make([][]int, len(groups))
+		best      = math.Inf(-1)
+		bestPicks [][]int
+	)
+	var search func(g, from, left, b int, value float64)
+	search = func(g, from, left, b int, value float64) {
+		if nodes++; nodes > maxOptimizerNodes {
+			return
+		}
+		if g == len(groups) {
+			if value > best {
+				best = value
+				bestPicks = make([][]int, len(chosen))
+				for k := range chosen {
+					bestPicks[k] = slices.Clone(chosen[k])
+				}
+			}
+			return
+		}
+		if value+bounds[g][at(g, from, left, b)] <= best+1e-9 {
+			return
+		}
+		candidates := groups[g].candidates
+		if left == 0 || from == len(candidates) {
+			search(g+1, 0, spotsOf(g+1), b, value)
+			return
+		}
+		if i := candidates[from]; !used[i] && extra[i] <= b {
+			used[i] = true
+			chosen[g] = append(chosen[g], i)
+			search(g, from+1, left-1, b-extra[i], value+players[i].Value)
+			chosen[g] = chosen[g][:len(chosen[g])-1]
+			used[i] = false
+		}
+		search(g, from+1, left, b, value)
+	}
+	search(0, 0, spotsOf(0), spare, 0)
+
+	result := OptimizerResult{Optimal: nodes <= maxOptimizerNodes, Spent: minBid * float64(spots)}
+	lineup := make(map[string][]int)
+	for g, picks := range bestPicks {
+		lineup[groups[g].slot.name] = picks
+	}
+	for _, slot := range o.roster.slots {
+		for k := 0; k < slot.spots; k++ {
+			spot := OptimizerSpot{Slot: slot.name, Player: -1}
+			if k < len(lineup[slot.name]) {
+				i := lineup[slot.name][k]
+				spot.Player, spot.Price = i, price(i)
+				result.Projected += players[i].Value
+				result.Spent += price(i) - minBid
+			}
+			result.Spots = append(result.Spots, spot)
+		}
+	}
+	return result
+}
+
+// snake finds the lineup with the most value the team's picks can draft, taking a player to
+// be there at any pick up to their ADP (players without one go after those with one, by
+// value). Which players are drafted at which picks, and who starts where, is a max-weight
+// flow: source -> pick -> player -> slot -> sink. Picks are chained so a pick can take any
+// player still there at a later one.
+func (o *Optimizer) snake(players []OptimizerPlayer) OptimizerResult {
+	picks := o.settings.Picks
+	adps := make([]float64, len(players))
+	values := make([]float64, len(players))
+	for i, player := range players {
+		adps[i], values[i] = player.ADP, player.Value
+	}
+	fillADP(adps, values)
+	// latest[i] is how many of the team's picks come before the player is gone
+	latest := make([]int, len(players))
+	for i, adp := range adps {
+		latest[i] = sort.Search(len(picks), func(k int) bool { return float64(picks[k]) > adp })
+	}
+
+	// Players gone by the same pick are interchangeable but for value, so only the best of
+	// them at each slot can start
+	groups := o.groups(players, func(i int) bool { return latest[i] > 0 })
+	chosen := make(map[int]bool)
+	var candidates []int
+	for _, group := range groups {
+		taken := make(map[int]int)
+		for _, i := range group.candidates {
+			if taken[latest[i]] < o.roster.Starters() {
+				taken[latest[i]]++
+				if !chosen[i] {
+					chosen[i] = true
+					candidates = append(candidates, i)
+				}
+			}
+		}
+	}
+	slices.Sort(candidates)
+
+	// Nodes: 0 source, 1..p picks, then each candidate's in and out, then slots, then the sink
+	p, n := len(picks), len(candidates)
+	in := func(c int) int { return p + 1 + 2*c }
+	slot := func(s int) int { return p + 1 + 2*n + s }
+	sink := p + 2*n + len(groups) + 1
+	graph := newFlowGraph(sink + 1)
+	for k := 1; k <= p; k++ {
+		graph.addEdge(0, k, 1, 0)
+		if k < p {
+			graph.addEdge(k, k+1, p, 0)
+		}
+	}
+	for c, i := range candidates {
+		graph.addEdge(latest[i], in(c), 1, 0)
+		graph.addEdge(in(c), in(c)+1, 1, -players[i].Value)
+		for s, group := range groups {
+			if slices.Contains(group.candidates, i) {
+				graph.addEdge(in(c)+1, slot(s), 1, 0)
+			}
+		}
+	}
+	for s, group := range groups {
+		graph.addEdge(slot(s), sink, group.slot.spots, 0)
+	}
+	graph.maxWeightFlow(0, sink)
+
+	lineup := make(map[string][]int)
+	var starters []int
+	for c, i := range candidates {
+		for _, e := range graph.edges[in(c)+1] {
+			if e.original && e.capacity == 0 {
+				name := groups[e.to-slot(0)].slot.name
+				lineup[name] = append(lineup[name], i)
+				starters = append(starters, i)
+			}
+		}
+	}
+	// The k-th starter to go takes the team's k-th pick, which the flow guarantees is in time
+	sort.SliceStable(starters, func(a, b int) bool { return adps[starters[a]] < adps[starters[b]] })
+	pickOf := make(map[int]int)
+	for k, i := range starters {
+		pickOf[i] = picks[k]
+	}
+
+	result := OptimizerResult{Optimal: true}
+	for _, slot := range o.roster.slots {
+		players := lineup[slot.name]
+		sort.SliceStable(players, func(a, b int) bool { return pickOf[players[a]] < pickOf[players[b]] })
+		for k := 0; k < slot.spots; k++ {
+			spot := OptimizerSpot{Slot: slot.name, Player: -1}
+			if k < len(players) {
+				spot.Player, spot.Pick = players[k], pickOf[players[k]]
+				result.Projected += values[players[k]]
+			}
+			result.Spots = append(result.Spots, spot)
+		}
+	}
+	return result
+}
+
+// groups lists the roster's starting slots with the players that can fill them, best first.
+// Only players worth something that pass include are considered.
+func (o *Optimizer) groups(players []OptimizerPlayer, include func(i int) bool) []optimizerGroup {
+	order := make([]int, len(players))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return players[order[a]].Value > players[order[b]].Value })
+
+	groups := make([]optimizerGroup, len(o.roster.slots))
+	for g, slot := range o.roster.slots {
+		groups[g].slot = slot
+		for _, i := range order {
+			if players[i].Value > 0 && include(i) && models.SlotAccepts(slot.name, players[i].Position, players[i].Eligible) {
+				groups[g].candidates = append(groups[g].candidates, i)
+			}
+		}
+	}
+	return groups
+}
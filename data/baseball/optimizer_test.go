@@ -0,0 +1,119 @@
+package baseball
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+// optimizerTerms are a two-team league starting a C and an OF with one bench spot
+var optimizerTerms = models.AuctionSettings{
+	Teams:  2,
+	Budget: 10,
+	MinBid: 1,
+	Roster: []models.RosterSlot{{Position: "C", Count: 1}, {Position: "OF", Count: 1}, {Position: "BN", Count: 1}},
+}
+
+func optimizerPlayers() []OptimizerPlayer {
+	player := func(position string, value, dollars, adp float64) OptimizerPlayer {
+		return OptimizerPlayer{RosterPlayer: RosterPlayer{Position: "batter", Eligible: []string{position}, Value: value}, Dollars: dollars, ADP: adp}
+	}
+	return []OptimizerPlayer{
+		player("C", 50, 6, 2),
+		player("C", 40, 2.4, 6), // bid in whole dollars, $2
+		player("OF", 60, 7, 1.5),
+		player("OF", 30, 1, 3),
+		player("OF", 20, 0.6, 0), // no ADP: gone after everyone with one
+	}
+}
+
+func TestOptimizerAuction(t *testing.T) {
+	// $3 covers the three spots at the minimum, leaving $7 over it: the $2 catcher and the $7
+	// outfielder use it all for 100, more than the best catcher with any outfielder left affordable
+	tests := []struct {
+		budget float64
+		want   OptimizerResult
+	}{
+		{
+			budget: 10,
+			want: OptimizerResult{Spots: []OptimizerSpot{
+				{Slot: "C", Player: 1, Price: 2},
+				{Slot: "OF", Player: 2, Price: 7},
+			}, Projected: 100, Spent: 10, Optimal: true},
+		},
+		{
+			// Without the $7 outfielder, the best catcher is worth the most
+			budget: 8,
+			want: OptimizerResult{Spots: []OptimizerSpot{
+				{Slot: "C", Player: 0, Price: 6},
+				{Slot: "OF", Player: 3, Price: 1},
+			}, Projected: 80, Spent: 8, Optimal: true},
+		},
+		{
+			// Only the minimum bids: the $1 outfielders, and no catcher
+			budget: 3,
+			want: OptimizerResult{Spots: []OptimizerSpot{
+				{Slot: "C", Player: -1},
+				{Slot: "OF", Player: 3, Price: 1},
+			}, Projected: 30, Spent: 3, Optimal: true},
+		},
+	}
+	for _, tt := range tests {
+		optimizer, err := NewOptimizer(models.OptimizeSettings{Budget: tt.budget}, optimizerTerms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := optimizer.Solve(optimizerPlayers()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("$%v: Solve = %+v, want %+v", tt.budget, got, tt.want)
+		}
+	}
+}
+
+func TestOptimizerSnake(t *testing.T) {
+	// Picking first of two, the team holds picks 1, 4 and 5. Only one of the players gone
+	// by pick 2 or 3 can be had, so pick 1 takes the best of them, the 60-point outfielder,
+	// and the 40-point catcher lasts to pick 4.
+	optimizer, err := NewOptimizer(models.OptimizeSettings{Slot: 1}, optimizerTerms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if picks := optimizer.Settings().Picks; !slices.Equal(picks, []int{1, 4, 5}) {
+		t.Errorf("picks = %v, want [1 4 5]", picks)
+	}
+	want := OptimizerResult{Spots: []OptimizerSpot{
+		{Slot: "C", Player: 1, Pick: 4},
+		{Slot: "OF", Player: 2, Pick: 1},
+	}, Projected: 100, Optimal: true}
+	if got := optimizer.Solve(optimizerPlayers()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Solve = %+v, want %+v", got, want)
+	}
+
+	// From picks 4 and 5, everyone ranked before pick 4 is gone: the team settles for the
+	// second catcher and the outfielder nobody ranked
+	optimizer, err = NewOptimizer(models.OptimizeSettings{Picks: []int{5, 4}}, optimizerTerms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := optimizer.Solve(optimizerPlayers())
+	if math.Abs(got.Projected-60) > 1e-9 || got.Spots[0].Player != 1 || got.Spots[1].Player != 4 {
+		t.Errorf("Solve from picks 4 and 5 = %+v, want the 40-point catcher and 20-point outfielder", got)
+	}
+}
+
+func TestNewOptimizerRejects(t *testing.T) {
+	for _, settings := range []models.OptimizeSettings{
+		{Budget: -1},
+		{Budget: 2},
+		{Slot: 3},
+		{Slot: 1, Picks: []int{2}},
+		{Picks: []int{0, 3}},
+		{Picks: []int{3, 3}},
+	} {
+		if _, err := NewOptimizer(settings, optimizerTerms); err == nil {
+			t.Errorf("NewOptimizer(%+v) succeeded, want an error", settings)
+		}
+	}
+}
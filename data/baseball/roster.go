@@ -0,0 +1,301 @@
+package baseball
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"super-fantasy-api/models"
+)
+
+// Eligibility defaults: projected games a pitcher needs to qualify as a starter or reliever
+const (
+	defaultMinStarts = 5
+	defaultMinRelief = 5
+)
+
+// Roster is the starting lineup of every team in a league, filled from a pool of players to
+// find each position's replacement level
+type Roster struct {
+	slots []leagueSlot
+	bench int // reserve spots across the league
+}
+
+// leagueSlot is one starting position and its spots across the league
+type leagueSlot struct {
+	name  string
+	spots int
+}
+
+// RosterPlayer is a player to fit into a roster: their role ("batter" or "pitcher"), the
+// positions they're eligible at (see Eligible) and their projected value
+type RosterPlayer struct {
+	Position string
+	Eligible []string
+	Value    float64
+}
+
+// RosterFit is where a player lands when the league's starting spots are filled
+type RosterFit struct {
+	Slot        string  // the starting slot the player fills, "" when they don't start
+	Replacement float64 // replacement level at the player's scarcest eligible slot
+}
+
+// NewRoster checks a league's roster slots, merging repeated ones ("OF" listed twice)
+func NewRoster(teams int, slots []models.RosterSlot) (*Roster, error) {
+	if teams <= 0 {
+		return nil, fmt.Errorf("teams must be positive")
+	}
+	roster := &Roster{}
+	for _, slot := range slots {
+		if slot.Count <= 0 || strings.TrimSpace(slot.Position) == "" {
+			return nil, fmt.Errorf("roster slots need a position and a positive count")
+		}
+		if models.BenchSlot(slot.Position) {
+			roster.bench += slot.Count * teams
+			continue
+		}
+		name := strings.ToUpper(strings.TrimSpace(slot.Position))
+		i := slices.IndexFunc(roster.slots, func(s leagueSlot) bool { return s.name == name })
+		if i < 0 {
+			roster.slots = append(roster.slots, leagueSlot{name: name})
+			i = len(roster.slots) - 1
+		}
+		roster.slots[i].spots += slot.Count * teams
+	}
+	return roster, nil
+}
+
+// Starters is the number of starting spots across the league
+func (r *Roster) Starters() int {
+	var spots int
+	for _, slot := range r.slots {
+		spots += slot.spots
+	}
+	return spots
+}
+
+// Bench is the number of reserve spots across the league
+func (r *Roster) Bench() int {
+	return r.bench
+}
+
+// Fill assigns players to the league's starting spots so the starters' total value is as
+// high as possible, which plays multi-position players where they're most needed: a catcher
+// who also qualifies at 1B catches. It returns where each player lands and each slot's
+// replacement level, the value of the best player left over who could fill it.
+//
+// A player's replacement level is the lowest of their eligible slots', so eligibility at a
+// scarce position (C, SS) raises their value over replacement.
+func (r *Roster) Fill(players []RosterPlayer) ([]RosterFit, map[string]float64) {
+	fits := make([]RosterFit, len(players))
+	for i, slot := range r.assign(players) {
+		if slot >= 0 {
+			fits[i].Slot = r.slots[slot].name
+		}
+	}
+
+	levels := make(map[string]float64)
+	for _, slot := range r.slots {
+		best := math.Inf(-1)
+		for i, player := range players {
+			if fits[i].Slot == "" && player.Value > best && models.SlotAccepts(slot.name, player.Position, player.Eligible) {
+				best = player.Value
+			}
+		}
+		if math.IsInf(best, -1) {
+			best = 0
+		}
+		levels[slot.name] = best
+	}
+
+	for i, player := range players {
+		fits[i].Replacement = player.Value // a player no slot takes is worth nothing over replacement
+		first := true
+		for _, slot := range r.slots {
+			if !models.SlotAccepts(slot.name, player.Position, player.Eligible) {
+				continue
+			}
+			if level := levels[slot.name]; first || level < fits[i].Replacement {
+				fits[i].Replacement = level
+				first = false
+			}
+		}
+	}
+	return fits, levels
+}
+
+// assign solves the slot assignment as a min-cost flow: source -> player -> slot -> sink, with
+// each slot's spots as its capacity and a player's negated value as the cost of starting them.
+// Only each slot's top Starters() eligible players are considered, which keeps the graph small
+// without losing the optimum: with fewer starters than that, a player starting at a slot
+// from further down could always be swapped for one of its top candidates left on the bench.
+// It returns each player's slot index, -1 for players who don't start.
+func (r *Roster) assign(players []RosterPlayer) []int {
+	assigned := make([]int, len(players))
+	for i := range assigned {
+		assigned[i] = -1
+	}
+
+	order := make([]int, len(players))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return players[order[a]].Value > players[order[b]].Value })
+
+	chosen := make(map[int]bool)
+	var candidates []int
+	for _, slot := range r.slots {
+		found := 0
+		for _, i := range order {
+			if found >= r.Starters() {
+				break
+			}
+			if models.SlotAccepts(slot.name, players[i].Position, players[i].Eligible) {
+				found++
+				if !chosen[i] {
+					chosen[i] = true
+					candidates = append(candidates, i)
+				}
+			}
+		}
+	}
+
+	// Nodes: 0 source, 1..n candidates, then slots, then the sink
+	n := len(candidates)
+	sink := n + len(r.slots) + 1
+	graph := newFlowGraph(sink + 1)
+	for c, i := range candidates {
+		graph.addEdge(0, c+1, 1, 0)
+		for s, slot := range r.slots {
+			if models.SlotAccepts(slot.name, players[i].Position, players[i].Eligible) {
+				graph.addEdge(c+1, n+1+s, 1, -players[i].Value)
+			}
+		}
+	}
+	for s, slot := range r.slots {
+		graph.addEdge(n+1+s, sink, slot.spots, 0)
+	}
+	graph.minCostFlow(0, sink)
+
+	for c, i := range candidates {
+		for _, e := range graph.edges[c+1] {
+			// A used forward edge to a slot is the player's assignment
+			if e.original && e.capacity == 0 {
+				assigned[i] = e.to - n - 1
+			}
+		}
+	}
+	return assigned
+}
+
+// flowGraph is a residual graph for min-cost flow
+type flowGraph struct {
+	edges [][]flowEdge
+}
+
+type flowEdge struct {
+	to, reverse int
+	capacity    int
+	cost        float64
+	original    bool // a forward edge, as opposed to a residual one
+}
+
+func newFlowGraph(nodes int) *flowGraph {
+	return &flowGraph{edges: make([][]flowEdge, nodes)}
+}
+
+func (g *flowGraph) addEdge(from, to, capacity int, cost float64) {
+	g.edges[from] = append(g.edges[from], flowEdge{to: to, reverse: len(g.edges[to]), capacity: capacity, cost: cost, original: true})
+	g.edges[to] = append(g.edges[to], flowEdge{to: from, reverse: len(g.edges[from]) - 1, cost: -cost})
+}
+
+// minCostFlow pushes as much flow as fits from source to sink, one cheapest path at a time
+// (Bellman-Ford with a queue, since costs are negative)
+func (g *flowGraph) minCostFlow(source, sink int) {
+	g.augment(source, sink, false)
+}
+
+// maxWeightFlow is minCostFlow that stops once the cheapest path no longer lowers the cost, so
+// with values as negated costs it finds the most valuable flow rather than the largest
+func (g *flowGraph) maxWeightFlow(source, sink int) {
+	g.augment(source, sink, true)
+}
+
+func (g *flowGraph) augment(source, sink int, profitableOnly bool) {
+	nodes := len(g.edges)
+	for {
+		dist := make([]float64, nodes)
+		for i := range dist {
+			dist[i] = math.Inf(1)
+		}
+		prevNode := make([]int, nodes)
+		prevEdge := make([]int, nodes)
+		queued := make([]bool, nodes)
+		dist[source] = 0
+		queue := []int{source}
+		queued[source] = true
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			queued[u] = false
+			for i, e := range g.edges[u] {
+				if e.capacity > 0 && dist[u]+e.cost < dist[e.to]-1e-9 {
+					dist[e.to] = dist[u] + e.cost
+					prevNode[e.to], prevEdge[e.to] = u, i
+					if !queued[e.to] {
+						queue = append(queue, e.to)
+						queued[e.to] = true
+					}
+				}
+			}
+		}
+		if math.IsInf(dist[sink], 1) || (profitableOnly && dist[sink] >= -1e-9) {
+			return
+		}
+		for v := sink; v != source; v = prevNode[v] {
+			e := &g.edges[prevNode[v]][prevEdge[v]]
+			e.capacity--
+			g.edges[v][e.reverse].capacity++
+		}
+	}
+}
+
+// Eligible combines the positions listed for a player with what their projection implies.
+// Batters keep their listed batting positions when their projected games reach the threshold
+// (or the line has no games); otherwise, or with none listed, they only fill UTIL and DH.
+// Pitchers are starters or relievers when listed as one, or when their projected starts or
+// relief appearances reach the thresholds.
+func Eligible(line Line, listed []string, settings models.EligibilitySettings) []string {
+	var eligible []string
+	if line.Position != "pitcher" {
+		if line.Has(models.StatG) && line.Get(models.StatG) < float64(settings.MinGames) {
+			return nil
+		}
+		for _, position := range listed {
+			if slices.Contains(models.FieldPositions, position) {
+				eligible = append(eligible, position)
+			}
+		}
+		return eligible
+	}
+
+	minStarts, minRelief := float64(settings.MinStarts), float64(settings.MinRelief)
+	if minStarts <= 0 {
+		minStarts = defaultMinStarts
+	}
+	if minRelief <= 0 {
+		minRelief = defaultMinRelief
+	}
+	starts := line.Get(models.StatGS)
+	relief := line.Get(models.StatG) - starts
+	if slices.Contains(listed, "SP") || starts >= minStarts {
+		eligible = append(eligible, "SP")
+	}
+	if slices.Contains(listed, "RP") || relief >= minRelief {
+		eligible = append(eligible, "RP")
+	}
+	return eligible
+}
@@ -0,0 +1,77 @@
+package baseball
+
+import (
+	"slices"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+func TestEligible(t *testing.T) {
+	batter := func(games float64) Line {
+		stats := map[models.Stat]float64{}
+		if games > 0 {
+			stats[models.StatG] = games
+		}
+		return Line{Position: "batter", Stats: stats}
+	}
+	pitcher := func(games, starts float64) Line {
+		return Line{Position: "pitcher", Stats: map[models.Stat]float64{models.StatG: games, models.StatGS: starts}}
+	}
+
+	tests := []struct {
+		name     string
+		line     Line
+		listed   []string
+		settings models.EligibilitySettings
+		want     []string
+	}{
+		{"batter keeps field positions", batter(150), []string{"OF", "RF", "DH", "SP"}, models.EligibilitySettings{}, []string{"OF", "RF", "DH"}},
+		{"batter under the games threshold", batter(15), []string{"SS"}, models.EligibilitySettings{MinGames: 20}, nil},
+		{"batter at the games threshold", batter(20), []string{"SS"}, models.EligibilitySettings{MinGames: 20}, []string{"SS"}},
+		{"batter without projected games", batter(0), []string{"SS"}, models.EligibilitySettings{MinGames: 20}, []string{"SS"}},
+		{"starter by projected starts", pitcher(30, 30), nil, models.EligibilitySettings{}, []string{"SP"}},
+		{"reliever by projected appearances", pitcher(60, 0), nil, models.EligibilitySettings{}, []string{"RP"}},
+		{"swingman", pitcher(40, 10), nil, models.EligibilitySettings{}, []string{"SP", "RP"}},
+		{"listed starter", pitcher(60, 0), []string{"SP"}, models.EligibilitySettings{}, []string{"SP", "RP"}},
+		{"raised thresholds", pitcher(40, 10), nil, models.EligibilitySettings{MinStarts: 12, MinRelief: 31}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Eligible(tt.line, tt.listed, tt.settings); !slices.Equal(got, tt.want) {
+				t.Errorf("Eligible = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRosterFillScarceSlot(t *testing.T) {
+	roster, err := NewRoster(1, []models.RosterSlot{{Position: "C", Count: 1}, {Position: "1B", Count: 2}, {Position: "OF", Count: 1}, {Position: "BN", Count: 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A dozen outfielders outrank everyone else, and the catchers who also play 1B are worth
+	// more there than the pure first basemen, so the pure catcher, ranked well down, catches
+	var players []RosterPlayer
+	for i := 0; i < 12; i++ {
+		players = append(players, RosterPlayer{Position: "batter", Eligible: []string{"OF"}, Value: float64(200 - i)})
+	}
+	players = append(players,
+		RosterPlayer{Position: "batter", Eligible: []string{"C", "1B"}, Value: 50},
+		RosterPlayer{Position: "batter", Eligible: []string{"C", "1B"}, Value: 49},
+		RosterPlayer{Position: "batter", Eligible: []string{"1B"}, Value: 30},
+		RosterPlayer{Position: "batter", Eligible: []string{"1B"}, Value: 29},
+		RosterPlayer{Position: "batter", Eligible: []string{"C"}, Value: 40},
+	)
+
+	fits, levels := roster.Fill(players)
+	want := map[int]string{0: "OF", 12: "1B", 13: "1B", 16: "C"}
+	for i, fit := range fits {
+		if fit.Slot != want[i] {
+			t.Errorf("player %d (%v) slot = %q, want %q", i, players[i].Value, fit.Slot, want[i])
+		}
+	}
+	if levels["C"] != 0 || levels["1B"] != 30 || levels["OF"] != 199 {
+		t.Errorf("replacement levels = %v, want C 0, 1B 30, OF 199", levels)
+	}
+}
@@ -0,0 +1,182 @@
+package baseball
+
+import (
+	"fmt"
+	"slices"
+
+	"super-fantasy-api/models"
+)
+
+// estimates are the formulas used for rules with Estimate set when a source doesn't project
+// the stat. The QS estimate credits a share of starts that grows with innings per start and
+// shrinks with ERA; it's a rough guide, not a model.
+var estimates = map[string]map[models.Stat]string{
+	"pitcher": {
+		models.StatQS: "GS * max(0, min(1, (IP / GS - 4) / 3)) * max(0, min(1, (6 - ERA) / 4))",
+	},
+}
+
+// Scorer applies a league's scoring rules to stat lines. NewScorer checks the rules and parses
+// every formula once, so build one per request and score every line with it.
+type Scorer struct {
+	rules []scoringRule
+}
+
+type scoringRule struct {
+	models.ScoringRule
+	stat     models.Stat // canonical stat, or the formula's name
+	formula  *Formula
+	estimate *Formula
+}
+
+// NewScorer validates and compiles the league's scoring rules (see LeagueSettings.ScoringRules)
+func NewScorer(settings models.LeagueSettings) (*Scorer, error) {
+	scorer := &Scorer{}
+	for i, rule := range settings.ScoringRules() {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %v", i+1, rule.Stat, err)
+		}
+		scorer.rules = append(scorer.rules, compiled)
+	}
+	return scorer, nil
+}
+
+func compileRule(rule models.ScoringRule) (scoringRule, error) {
+	compiled := scoringRule{ScoringRule: rule, stat: models.Stat(rule.Stat)}
+	if rule.Position != "batter" && rule.Position != "pitcher" {
+		return compiled, fmt.Errorf("position must be 'batter' or 'pitcher'")
+	}
+	if rule.Stat == "" {
+		return compiled, fmt.Errorf("stat is required")
+	}
+
+	if rule.Formula != "" {
+		formula, err := ParseFormula(rule.Formula)
+		if err != nil {
+			return compiled, err
+		}
+		for _, name := range formula.Names() {
+			if _, ok := models.LookupStat(name); !ok {
+				return compiled, fmt.Errorf("unknown stat %q in formula", name)
+			}
+		}
+		compiled.formula = formula
+		return compiled, nil
+	}
+
+	stat, ok := models.LookupStat(rule.Stat)
+	if !ok {
+		return compiled, fmt.Errorf("unknown stat; define it with a formula")
+	}
+	compiled.stat = stat
+	if rule.Estimate {
+		expression, ok := estimates[rule.Position][stat]
+		if !ok {
+			return compiled, fmt.Errorf("no estimate for %s %s", rule.Position, stat)
+		}
+		compiled.estimate, _ = ParseFormula(expression)
+	}
+	return compiled, nil
+}
+
+// Score converts a normalized line to fantasy points with the league's settings, reporting
+// rules that don't compile. Handlers should build a Scorer instead; this is for callers
+// scoring a single line.
+func Score(line Line, settings models.LeagueSettings) (models.PlayerProjection, error) {
+	scorer, err := NewScorer(settings)
+	if err != nil {
+		return models.PlayerProjection{}, err
+	}
+	return scorer.Score(line), nil
+}
+
+// Score applies the rules for the line's position and breaks the total down by stat. A rule whose stat the source doesn't
+// project (and can't be estimated) scores nothing and is listed in Unscored, so a low total
+// can be told apart from missing data.
+func (s *Scorer) Score(line Line) models.PlayerProjection {
+	projection := models.PlayerProjection{PlayerName: line.Name, Breakdown: make(map[models.Stat]models.StatPoints)}
+	for _, rule := range s.rules {
+		if rule.Position != line.Position || rule.Weight == 0 {
+			continue
+		}
+		value, ok := rule.value(line)
+		if !ok {
+			projection.Unscored = append(projection.Unscored, rule.stat)
+			continue
+		}
+		points := rule.points(value)
+		projection.TotalPoints += points
+
+		// Rules scoring the same stat twice (per unit and a bonus) share one entry
+		entry := projection.Breakdown[rule.stat]
+		entry.Value = value
+		entry.Points += points
+		projection.Breakdown[rule.stat] = entry
+	}
+	return projection
+}
+
+// Stats lists the stats the rules score, in rule order, for breakdown columns
+func (s *Scorer) Stats() []models.Stat {
+	var stats []models.Stat
+	for _, rule := range s.rules {
+		if rule.Weight != 0 && !slices.Contains(stats, rule.stat) {
+			stats = append(stats, rule.stat)
+		}
+	}
+	return stats
+}
+
+// value reads or computes the rule's stat from a line
+func (r scoringRule) value(line Line) (float64, bool) {
+	lookup := func(name string) (float64, bool) {
+		stat, _ := models.LookupStat(name)
+		value, ok := line.Stats[stat]
+		return value, ok
+	}
+	if r.formula != nil {
+		return r.formula.Eval(lookup)
+	}
+	if value, ok := line.Stats[r.stat]; ok {
+		return value, true
+	}
+	if r.estimate != nil {
+		return r.estimate.Eval(lookup)
+	}
+	return 0, false
+}
+
+// points applies the weight per unit, or once as a bonus when the rule has a threshold
+func (r scoringRule) points(value float64) float64 {
+	if r.Threshold == nil {
+		return value * r.Weight
+	}
+	if r.AtMost && value <= *r.Threshold || !r.AtMost && value >= *r.Threshold {
+		return r.Weight
+	}
+	return 0
+}
+
+// Coverage lists, for every registered source and position, the rules the source can't score
+// because it doesn't project a stat they need
+func (s *Scorer) Coverage() []models.RuleCoverage {
+	coverage := []models.RuleCoverage{}
+	for _, source := range Sources() {
+		for _, position := range source.Positions() {
+			line := Line{Position: position, Stats: make(map[models.Stat]float64)}
+			for _, stat := range source.Stats(position) {
+				line.Stats[stat] = 0
+			}
+			for _, rule := range s.rules {
+				if rule.Position != position {
+					continue
+				}
+				if _, ok := rule.value(line); !ok {
+					coverage = append(coverage, models.RuleCoverage{Source: source.Name(), Position: position, Stat: string(rule.stat)})
+				}
+			}
+		}
+	}
+	return coverage
+}
@@ -0,0 +1,84 @@
+package baseball
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+func TestScorerScore(t *testing.T) {
+	threshold := func(value float64) *float64 { return &value }
+	scorer, err := NewScorer(models.LeagueSettings{Rules: []models.ScoringRule{
+		{Position: "batter", Stat: "HR", Weight: 4},
+		{Position: "batter", Stat: "HR", Weight: 10, Threshold: threshold(30)},
+		{Position: "batter", Stat: "XBH", Formula: "2B + 3B + HR", Weight: 1},
+		{Position: "batter", Stat: "SB", Weight: 2},
+		{Position: "batter", Stat: "WAR", Weight: 0},
+		{Position: "pitcher", Stat: "ERA", Weight: 5, Threshold: threshold(3), AtMost: true},
+		{Position: "pitcher", Stat: "QS", Weight: 3, Estimate: true},
+		{Position: "pitcher", Stat: "SV", Weight: 5},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batter := scorer.Score(Line{Name: "Batter", Position: "batter", Stats: map[models.Stat]float64{
+		models.Stat2B: 30, models.Stat3B: 2, models.StatHR: 35, models.StatWAR: 5,
+	}})
+	// 35 homers at 4, the 30-homer bonus, and 67 extra-base hits; no steals projected
+	if want := 35*4 + 10 + 67.0; math.Abs(batter.TotalPoints-want) > 1e-9 {
+		t.Errorf("batter total = %v, want %v", batter.TotalPoints, want)
+	}
+	if hr := batter.Breakdown[models.StatHR]; hr.Value != 35 || hr.Points != 150 {
+		t.Errorf("HR breakdown = %+v, want both rules in one entry", hr)
+	}
+	if _, ok := batter.Breakdown[models.StatWAR]; ok {
+		t.Error("a zero-weight rule was scored")
+	}
+	if !slices.Equal(batter.Unscored, []models.Stat{models.StatSB}) {
+		t.Errorf("batter unscored = %v, want [SB]", batter.Unscored)
+	}
+
+	// Six-inning starts with a 2.00 ERA earn every start a quality start
+	pitcher := scorer.Score(Line{Name: "Pitcher", Position: "pitcher", Stats: map[models.Stat]float64{
+		models.StatGS: 30, models.StatIP: 210, models.StatERA: 2,
+	}})
+	if want := 5 + 30*3.0; math.Abs(pitcher.TotalPoints-want) > 1e-9 {
+		t.Errorf("pitcher total = %v, want %v", pitcher.TotalPoints, want)
+	}
+	if !slices.Equal(pitcher.Unscored, []models.Stat{models.StatSV}) {
+		t.Errorf("pitcher unscored = %v, want [SV]", pitcher.Unscored)
+	}
+
+	if want := []models.Stat{models.StatHR, "XBH", models.StatSB, models.StatERA, models.StatQS, models.StatSV}; !slices.Equal(scorer.Stats(), want) {
+		t.Errorf("Stats = %v, want %v", scorer.Stats(), want)
+	}
+}
+
+func TestNewScorerRejects(t *testing.T) {
+	for _, rule := range []models.ScoringRule{
+		{Position: "fielder", Stat: "HR", Weight: 1},
+		{Position: "batter", Weight: 1},
+		{Position: "batter", Stat: "XBH", Weight: 1},
+		{Position: "batter", Stat: "XBH", Formula: "2B + 3B +", Weight: 1},
+		{Position: "batter", Stat: "XBH", Formula: "2B + XB", Weight: 1},
+		{Position: "batter", Stat: "HR", Weight: 1, Estimate: true},
+	} {
+		if _, err := NewScorer(models.LeagueSettings{Rules: []models.ScoringRule{rule}}); err == nil {
+			t.Errorf("NewScorer(%+v) succeeded, want an error", rule)
+		}
+	}
+}
+
+func TestScoreReportsBrokenRules(t *testing.T) {
+	line := Line{Name: "Batter", Position: "batter", Stats: map[models.Stat]float64{models.StatHR: 30}}
+	if _, err := Score(line, models.LeagueSettings{Rules: []models.ScoringRule{{Position: "batter", Stat: "XBH", Weight: 1}}}); err == nil {
+		t.Error("Score with an unknown stat succeeded, want an error")
+	}
+	projection, err := Score(line, models.LeagueSettings{Rules: []models.ScoringRule{{Position: "batter", Stat: "HR", Weight: 4}}})
+	if err != nil || projection.TotalPoints != 120 {
+		t.Errorf("Score = %v, %v; want 120 points", projection.TotalPoints, err)
+	}
+}
@@ -0,0 +1,193 @@
+package baseball
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"runtime"
+	"slices"
+	"sort"
+	"sync"
+
+	"super-fantasy-api/models"
+)
+
+// Simulation defaults
+const (
+	defaultSeasons         = 1000
+	maxSeasons             = 10000
+	defaultPlayingTimeRisk = 0.15
+	defaultMinSpread       = 0.1
+	maxPlayingTime         = 1.25 // a season can't run much past the projected playing time
+)
+
+// rateStats aren't sampled: the ones with counts behind them are recomputed from the sampled
+// counts, the rest stay at the projection
+var rateStats = []models.Stat{
+	models.StatAVG, models.StatOBP, models.StatSLG, models.StatOPS, models.StatWOBA, models.StatERA, models.StatWHIP,
+}
+
+// Simulation samples seasons around players' projections and scores them. NewSimulation
+// checks the settings once.
+type Simulation struct {
+	settings models.SimulationSettings
+	scorer   *Scorer
+}
+
+// SimulatedPlayer is a player to simulate: a key that seeds their draws, the combined
+// projection seasons are sampled around, and each source's line, whose disagreement sets
+// how widely each stat varies
+type SimulatedPlayer struct {
+	Key   string
+	Line  Line
+	Lines []Line
+}
+
+// Outcome is the distribution of a player's simulated points
+type Outcome struct {
+	Mean, P10, P50, P90 float64
+}
+
+// NewSimulation validates the settings, filling in defaults for what's left zero
+func NewSimulation(settings models.SimulationSettings, scorer *Scorer) (*Simulation, error) {
+	if settings.Seasons < 0 || settings.Seasons > maxSeasons {
+		return nil, fmt.Errorf("seasons must be between 1 and %d", maxSeasons)
+	}
+	if settings.Workers < 0 || settings.PlayingTimeRisk < 0 || settings.MinSpread < 0 {
+		return nil, fmt.Errorf("workers, playing_time_risk and min_spread can't be negative")
+	}
+	if settings.Seasons == 0 {
+		settings.Seasons = defaultSeasons
+	}
+	if settings.Workers == 0 {
+		settings.Workers = runtime.NumCPU()
+	}
+	if settings.PlayingTimeRisk == 0 {
+		settings.PlayingTimeRisk = defaultPlayingTimeRisk
+	}
+	if settings.MinSpread == 0 {
+		settings.MinSpread = defaultMinSpread
+	}
+	return &Simulation{settings: settings, scorer: scorer}, nil
+}
+
+// Run simulates every player on a pool of workers, returning one outcome per player in the
+// same order. Each player's draws come from their own generator, seeded from the settings'
+// seed and the player's key, so results don't depend on the worker count or player order.
+func (s *Simulation) Run(players []SimulatedPlayer) []Outcome {
+	outcomes := make([]Outcome, len(players))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(s.settings.Workers, max(len(players), 1)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				outcomes[i] = s.simulate(players[i])
+			}
+		}()
+	}
+	for i := range players {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return outcomes
+}
+
+// simulate plays out one player's seasons. Each season draws a playing-time multiplier shared
+// by all the player's counting stats (an injury costs homers and runs alike), then varies each
+// stat independently by the sources' disagreement on it, but never by less than MinSpread of
+// its projection.
+func (s *Simulation) simulate(player SimulatedPlayer) Outcome {
+	rng := rand.New(rand.NewSource(s.playerSeed(player.Key)))
+	spreads := statSpreads(player.Lines)
+	// draws go to the stats in a fixed order, not the map's
+	stats := make([]models.Stat, 0, len(player.Line.Stats))
+	for stat := range player.Line.Stats {
+		stats = append(stats, stat)
+	}
+	slices.Sort(stats)
+
+	points := make([]float64, s.settings.Seasons)
+	for season := range points {
+		playingTime := math.Max(0, math.Min(maxPlayingTime, 1+s.settings.PlayingTimeRisk*rng.NormFloat64()))
+		line := player.Line
+		line.Stats = make(map[models.Stat]float64, len(player.Line.Stats))
+		for _, stat := range stats {
+			projected := player.Line.Stats[stat]
+			if slices.Contains(rateStats, stat) {
+				line.Stats[stat] = projected
+				continue
+			}
+			spread := math.Max(spreads[stat], s.settings.MinSpread*math.Abs(projected))
+			value := playingTime * (projected + spread*rng.NormFloat64())
+			if stat != models.StatWAR {
+				value = math.Max(0, value)
+			}
+			line.Stats[stat] = value
+		}
+		deriveCounts(line)
+		recomputeRates(line)
+		points[season] = s.scorer.Score(line).TotalPoints
+	}
+
+	sort.Float64s(points)
+	mean, _ := meanStdDev(points)
+	return Outcome{
+		Mean: mean,
+		P10:  percentile(points, 0.1),
+		P50:  percentile(points, 0.5),
+		P90:  percentile(points, 0.9),
+	}
+}
+
+// playerSeed mixes the run's seed with the player's key
+func (s *Simulation) playerSeed(key string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+	return s.settings.Seed ^ int64(hash.Sum64())
+}
+
+// statSpreads is the standard deviation of each stat across the sources that project it
+func statSpreads(lines []Line) map[models.Stat]float64 {
+	values := make(map[models.Stat][]float64)
+	for _, line := range lines {
+		for stat, value := range line.Stats {
+			values[stat] = append(values[stat], value)
+		}
+	}
+	spreads := make(map[models.Stat]float64, len(values))
+	for stat, v := range values {
+		_, spreads[stat] = meanStdDev(v)
+	}
+	return spreads
+}
+
+// deriveCounts brings a sampled line's composite counts back in step with their parts: hits
+// and total bases from the hit types, outs from innings
+func deriveCounts(line Line) {
+	s := line.Stats
+	if line.Position == "pitcher" {
+		if line.Has(models.StatIP) {
+			s[models.StatOuts] = 3 * s[models.StatIP]
+		}
+		return
+	}
+	if line.Has(models.Stat1B) && line.Has(models.Stat2B) && line.Has(models.Stat3B) && line.Has(models.StatHR) {
+		s[models.StatH] = s[models.Stat1B] + s[models.Stat2B] + s[models.Stat3B] + s[models.StatHR]
+		s[models.StatTB] = s[models.Stat1B] + 2*s[models.Stat2B] + 3*s[models.Stat3B] + 4*s[models.StatHR]
+	}
+}
+
+// percentile reads the p quantile from sorted values, interpolating between neighbours
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
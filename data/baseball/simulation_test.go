@@ -0,0 +1,89 @@
+package baseball
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+// simulatedPlayers are batters whose sources disagree by a different amount each
+func simulatedPlayers() []SimulatedPlayer {
+	var players []SimulatedPlayer
+	for i := 0; i < 12; i++ {
+		var lines []Line
+		for j, source := range []string{"fangraphs_steamer", "fangraphs_zips", "fantasypros"} {
+			shift := float64((j - 1) * i)
+			lines = append(lines, Line{Name: fmt.Sprintf("Player %d", i), Source: source, Position: "batter", Stats: map[models.Stat]float64{
+				models.StatAB: 550 + 5*shift, models.Stat1B: 100 + shift, models.Stat2B: 30, models.Stat3B: 3,
+				models.StatHR: 20 + shift, models.StatR: 80, models.StatRBI: 75 + shift, models.StatBB: 50, models.StatSB: 10,
+			}})
+		}
+		players = append(players, SimulatedPlayer{Key: fmt.Sprintf("p%d", i), Line: Consensus(lines), Lines: lines})
+	}
+	return players
+}
+
+func TestSimulationSeedReproducible(t *testing.T) {
+	scorer, err := NewScorer(models.LeagueSettings{Rules: []models.ScoringRule{
+		{Position: "batter", Stat: "HR", Weight: 4},
+		{Position: "batter", Stat: "R", Weight: 1},
+		{Position: "batter", Stat: "RBI", Weight: 1},
+		{Position: "batter", Stat: "BB", Weight: 1},
+		{Position: "batter", Stat: "SB", Weight: 2},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	players := simulatedPlayers()
+	run := func(seed int64, workers int, players []SimulatedPlayer) []Outcome {
+		simulation, err := NewSimulation(models.SimulationSettings{Seasons: 200, Seed: seed, Workers: workers}, scorer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return simulation.Run(players)
+	}
+
+	first := run(7, 1, players)
+	if !reflect.DeepEqual(run(7, 8, players), first) {
+		t.Error("the same seed gave different outcomes on 1 and 8 workers")
+	}
+	// A player's draws don't depend on who else is simulated
+	reversed := make([]SimulatedPlayer, len(players))
+	for i, player := range players {
+		reversed[len(players)-1-i] = player
+	}
+	for i, outcome := range run(7, 4, reversed) {
+		if outcome != first[len(players)-1-i] {
+			t.Errorf("%s came out %+v in reverse order, %+v in order", reversed[i].Key, outcome, first[len(players)-1-i])
+		}
+	}
+	if reflect.DeepEqual(run(8, 1, players), first) {
+		t.Error("different seeds gave the same outcomes")
+	}
+
+	for i, outcome := range first {
+		if !(outcome.P10 <= outcome.P50 && outcome.P50 <= outcome.P90) || outcome.P10 == outcome.P90 {
+			t.Errorf("player %d percentiles out of order or flat: %+v", i, outcome)
+		}
+	}
+}
+
+func TestNewSimulationRejects(t *testing.T) {
+	scorer, err := NewScorer(models.LeagueSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, settings := range []models.SimulationSettings{
+		{Seasons: -1},
+		{Seasons: maxSeasons + 1},
+		{Workers: -1},
+		{PlayingTimeRisk: -0.1},
+		{MinSpread: -0.1},
+	} {
+		if _, err := NewSimulation(settings, scorer); err == nil {
+			t.Errorf("NewSimulation(%+v) succeeded, want an error", settings)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package baseball
+
+import (
+	"fmt"
+	"slices"
+
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+)
+
+// ProjectionSource adapts one projection system's CSV exports. Sources register themselves at
+// init; uploads, projections and the export all work from the registry, so adding a source
+// means adding an adapter rather than editing every handler.
+type ProjectionSource interface {
+	Name() string        // stored source name, e.g. "fangraphs_atc"
+	Label() string       // export column, e.g. "FangraphsATC"
+	Positions() []string // "batter" and/or "pitcher"
+	// Detect reports which position a CSV header is an export for, if it's this source's format
+	Detect(header []string) (position string, ok bool)
+	// NewRow returns an empty row to decode one CSV record (or stored document) into
+	NewRow(position string) Row
+	// Stats lists the stats the source's export for a position can carry
+	Stats(position string) []models.Stat
+}
+
+// Row is one decoded row of a source's export. It's also the document stored for the row.
+type Row interface {
+	// Normalize fills in what the export leaves implicit: canonical team, derived stats and
+	// the upload's year, source and position
+	Normalize(meta RowMeta)
+	// Link records the registry player the row was matched to
+	Link(playerID string)
+	// Line converts the row to the normalized stat line
+	Line() Line
+}
+
+// RowMeta is the upload metadata stamped on every row
+type RowMeta struct {
+	Year        string
+	Source      string
+	Position    string
+	Unprojected []models.Stat // stats the row's export doesn't project (see DecodeRow)
+}
+
+var (
+	sources       []ProjectionSource
+	sourcesByName = make(map[string]ProjectionSource)
+)
+
+// RegisterSource adds a source to the registry. Names must be unique.
+func RegisterSource(source ProjectionSource) {
+	if _, exists := sourcesByName[source.Name()]; exists {
+		panic(fmt.Sprintf("projection source %q registered twice", source.Name()))
+	}
+	sources = append(sources, source)
+	sourcesByName[source.Name()] = source
+}
+
+// Sources lists the registered sources in registration order
+func Sources() []ProjectionSource {
+	return sources
+}
+
+// LookupSource finds a registered source by its stored name
+func LookupSource(name string) (ProjectionSource, bool) {
+	source, ok := sourcesByName[name]
+	return source, ok
+}
+
+// SourceName builds the stored source name for a request's source and suffix,
+// e.g. "fangraphs" + "steamer" = "fangraphs_steamer"
+func SourceName(source, suffix string) string {
+	if suffix == "" {
+		return source
+	}
+	return source + "_" + suffix
+}
+
+// SourceNames lists the registered source names, for error messages
+func SourceNames() []string {
+	names := make([]string, len(sources))
+	for i, source := range sources {
+		names[i] = source.Name()
+	}
+	return names
+}
+
+// DetectSource finds the first registered source whose format matches a CSV header. Systems
+// that share one site's export format can't be told apart by header, so this identifies the
+// site (the first one registered for it); the suffix picks the system.
+func DetectSource(header []string) (ProjectionSource, string, bool) {
+	for _, source := range sources {
+		if position, ok := source.Detect(header); ok {
+			return source, position, true
+		}
+	}
+	return nil, "", false
+}
+
+// Supports reports whether a source projects the given position
+func Supports(source ProjectionSource, position string) bool {
+	return slices.Contains(source.Positions(), position)
+}
+
+// DecodeRow decodes one CSV record into row and normalizes it. Stats whose column the export
+// doesn't have, or leaves blank on this row, are recorded as not projected rather than zero.
+func DecodeRow(columns *utils.ColumnMap, record []string, row Row, meta RowMeta) []utils.CellError {
+	cells := columns.Decode(record, row)
+	meta.Unprojected = nil
+	for _, name := range columns.Absent() {
+		if stat, ok := models.LookupStat(name); ok {
+			meta.Unprojected = append(meta.Unprojected, stat)
+		}
+	}
+	for _, cell := range cells {
+		if stat, ok := models.LookupStat(cell.Name); ok && cell.Blank {
+			meta.Unprojected = append(meta.Unprojected, stat)
+		}
+	}
+	row.Normalize(meta)
+	return cells
+}
+
+// structSource is a ProjectionSource whose exports decode into one csv-tagged struct per
+// position. Every current site works this way; only the name, label and positions differ.
+type structSource struct {
+	name    string
+	label   string
+	batter  func() Row
+	pitcher func() Row
+}
+
+func (s structSource) Name() string  { return s.name }
+func (s structSource) Label() string { return s.label }
+
+func (s structSource) Positions() []string {
+	var positions []string
+	if s.batter != nil {
+		positions = append(positions, "batter")
+	}
+	if s.pitcher != nil {
+		positions = append(positions, "pitcher")
+	}
+	return positions
+}
+
+// Detect accepts a header when it carries every required column of the position's struct
+func (s structSource) Detect(header []string) (string, bool) {
+	for _, position := range s.Positions() {
+		if _, err := utils.MapColumns(header, s.NewRow(position)); err == nil {
+			return position, true
+		}
+	}
+	return "", false
+}
+
+// Stats are the ones an empty row's line has: every stat the struct carries plus derived ones
+func (s structSource) Stats(position string) []models.Stat {
+	row := s.NewRow(position)
+	if row == nil {
+		return nil
+	}
+	line := row.Line()
+	var stats []models.Stat
+	for _, stat := range slices.Concat(models.BattingStats, models.PitchingStats) {
+		if line.Has(stat) && !slices.Contains(stats, stat) {
+			stats = append(stats, stat)
+		}
+	}
+	return stats
+}
+
+func (s structSource) NewRow(position string) Row {
+	switch {
+	case position == "batter" && s.batter != nil:
+		return s.batter()
+	case position == "pitcher" && s.pitcher != nil:
+		return s.pitcher()
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package baseball
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+)
+
+// Headers as the sites export them
+var (
+	fangraphsBatterHeader    = strings.Split("#,Name,Team,G,AB,PA,H,1B,2B,3B,HR,R,RBI,BB,IBB,SO,HBP,SF,SH,SB,CS,AVG", ",")
+	fangraphsPitcherHeader   = strings.Split("#,Name,Team,W,L,ERA,G,GS,SV,HLD,BS,IP,TBF,H,R,ER,HR,BB,IBB,HBP,SO", ",")
+	fantasyProsBatterHeader  = strings.Split("Player,Team,Positions,AB,R,HR,RBI,SB,AVG,OBP,H,2B,3B,BB,SO,SLG,OPS", ",")
+	fantasyProsPitcherHeader = strings.Split("Player,Team,Positions,IP,K,W,SV,ERA,WHIP,ER,H,BB,HR,G,GS,L,CG", ",")
+)
+
+func TestDetectSource(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       []string
+		wantSource   string
+		wantPosition string
+	}{
+		// Every FanGraphs system shares one format, so detection names the site
+		{name: "fangraphs batters", header: fangraphsBatterHeader, wantSource: "fangraphs", wantPosition: "batter"},
+		{name: "fangraphs pitchers", header: fangraphsPitcherHeader, wantSource: "fangraphs", wantPosition: "pitcher"},
+		{name: "fantasypros batters", header: fantasyProsBatterHeader, wantSource: "fantasypros", wantPosition: "batter"},
+		{name: "fantasypros pitchers", header: fantasyProsPitcherHeader, wantSource: "fantasypros", wantPosition: "pitcher"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, position, ok := DetectSource(tt.header)
+			if !ok {
+				t.Fatal("header not detected")
+			}
+			if source.Name() != tt.wantSource || position != tt.wantPosition {
+				t.Errorf("DetectSource = %s %s, want %s %s", source.Name(), position, tt.wantSource, tt.wantPosition)
+			}
+		})
+	}
+
+	if source, _, ok := DetectSource([]string{"Name", "Team", "HR"}); ok {
+		t.Errorf("a partial header was detected as %s", source.Name())
+	}
+}
+
+func TestSourceRegistry(t *testing.T) {
+	for _, name := range []string{
+		"fangraphs", "fangraphs_steamer", "fangraphs_zips", "fangraphs_zipsdc", "fangraphs_thebat",
+		"fangraphs_depthcharts", "fangraphs_oopsy", "fangraphs_atc", "fangraphs_batx", "fantasypros",
+	} {
+		if !slices.Contains(SourceNames(), name) {
+			t.Errorf("%s not registered", name)
+		}
+	}
+	if len(SourceNames()) != len(Sources()) {
+		t.Errorf("%d names for %d sources", len(SourceNames()), len(Sources()))
+	}
+	if _, ok := LookupSource("fangraphs_zzz"); ok {
+		t.Error("LookupSource found an unregistered source")
+	}
+
+	// ATC only projects pitchers and BatX only batters
+	tests := []struct {
+		source, position string
+		want             bool
+	}{
+		{"fangraphs_steamer", "batter", true},
+		{"fangraphs_steamer", "pitcher", true},
+		{"fangraphs_atc", "batter", false},
+		{"fangraphs_atc", "pitcher", true},
+		{"fangraphs_batx", "batter", true},
+		{"fangraphs_batx", "pitcher", false},
+	}
+	for _, tt := range tests {
+		source, ok := LookupSource(tt.source)
+		if !ok {
+			t.Fatalf("LookupSource(%q) found nothing", tt.source)
+		}
+		if got := Supports(source, tt.position); got != tt.want {
+			t.Errorf("Supports(%s, %s) = %v, want %v", tt.source, tt.position, got, tt.want)
+		}
+		if row := source.NewRow(tt.position); (row != nil) != tt.want {
+			t.Errorf("%s NewRow(%s) = %v", tt.source, tt.position, row)
+		}
+	}
+	// A source that doesn't project a position doesn't detect its exports either
+	atc, _ := LookupSource("fangraphs_atc")
+	if _, ok := atc.Detect(fangraphsBatterHeader); ok {
+		t.Error("ATC detected a batter export")
+	}
+}
+
+func TestSourceName(t *testing.T) {
+	if got := SourceName("fangraphs", "steamer"); got != "fangraphs_steamer" {
+		t.Errorf("SourceName = %q, want fangraphs_steamer", got)
+	}
+	if got := SourceName("fantasypros", ""); got != "fantasypros" {
+		t.Errorf("SourceName without a suffix = %q, want fantasypros", got)
+	}
+}
+
+func TestDecodeRow(t *testing.T) {
+	source, position, ok := DetectSource(fangraphsBatterHeader)
+	if !ok {
+		t.Fatal("header not detected")
+	}
+	columns, err := utils.MapColumns(fangraphsBatterHeader, source.NewRow(position))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := strings.Split("1,Aaron Judge,NYY,150,550,680,160,90,30,1,40,110,120,100,15,160,,4,0,8,2,.291", ",")
+	row := source.NewRow(position)
+	cells := DecodeRow(columns, record, row, RowMeta{Year: "2025", Source: "fangraphs_zips", Position: position})
+	if len(cells) != 1 || !cells[0].Blank || cells[0].Name != "HBP" {
+		t.Errorf("cell errors = %+v, want HBP blank", cells)
+	}
+
+	// Blank cells and the optional columns the export leaves out aren't projected as zero
+	line := row.Line()
+	if line.Source != "fangraphs_zips" || line.Year != "2025" || line.Get(models.StatHR) != 40 {
+		t.Errorf("line = %+v", line)
+	}
+	for _, stat := range []models.Stat{models.StatHBP, models.StatWOBA, models.StatWAR} {
+		if line.Has(stat) {
+			t.Errorf("%s projected as %v", stat, line.Get(stat))
+		}
+	}
+	if !line.Has(models.StatIBB) || line.Get(models.StatIBB) != 15 {
+		t.Errorf("IBB = %v, want 15", line.Get(models.StatIBB))
+	}
+}
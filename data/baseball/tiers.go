@@ -0,0 +1,171 @@
+package baseball
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"super-fantasy-api/models"
+)
+
+// Tier defaults
+const (
+	defaultTierCount = 6
+	maxTierCount     = 20
+	defaultTierDepth = 40
+)
+
+// Tiering groups each position's best players into tiers of similar value. NewTiering checks
+// the settings once.
+type Tiering struct {
+	settings models.TierSettings
+}
+
+// Tier is one tier at a position: the players in it, as indexes into the players tiered, best
+// first
+type Tier struct {
+	Position string
+	Number   int // 1 is the best tier
+	Players  []int
+	High     float64
+	Low      float64
+}
+
+// NewTiering validates the tier settings, filling in defaults for what's left zero
+func NewTiering(settings models.TierSettings) (*Tiering, error) {
+	if settings.Count < 0 || settings.Count > maxTierCount {
+		return nil, fmt.Errorf("count must be between 1 and %d", maxTierCount)
+	}
+	if settings.Depth < 0 {
+		return nil, fmt.Errorf("depth can't be negative")
+	}
+	for position, count := range settings.Positions {
+		if !slices.Contains(models.TierPositions, position) {
+			return nil, fmt.Errorf("unknown position %q in positions", position)
+		}
+		if count < 1 || count > maxTierCount {
+			return nil, fmt.Errorf("tier count for %s must be between 1 and %d", position, maxTierCount)
+		}
+	}
+	if settings.Count == 0 {
+		settings.Count = defaultTierCount
+	}
+	if settings.Depth == 0 {
+		settings.Depth = defaultTierDepth
+	}
+	return &Tiering{settings: settings}, nil
+}
+
+// Count is the number of tiers at a position
+func (t *Tiering) Count(position string) int {
+	if count, ok := t.settings.Positions[position]; ok {
+		return count
+	}
+	return t.settings.Count
+}
+
+// Tiers groups each position's top players (Depth of them) into tiers, listing the tiers in
+// position order, best tier first. A player is tiered at every position they're eligible at
+// (see TierPositions). Tiers are the optimal one-dimensional k-means clustering of the players'
+// values: the split into Count groups with the least squared distance from each group's mean,
+// so a tier breaks where there's a gap in value. Players with equal values are taken in the
+// order given, so the same players in the same order always get the same tiers.
+func (t *Tiering) Tiers(players []RosterPlayer) []Tier {
+	byPosition := make(map[string][]int)
+	for i, player := range players {
+		for _, position := range TierPositions(player) {
+			byPosition[position] = append(byPosition[position], i)
+		}
+	}
+
+	var tiers []Tier
+	for _, position := range models.TierPositions {
+		candidates := byPosition[position]
+		if len(candidates) == 0 {
+			continue
+		}
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return players[candidates[a]].Value > players[candidates[b]].Value
+		})
+		candidates = candidates[:min(len(candidates), t.settings.Depth)]
+
+		values := make([]float64, len(candidates))
+		for i, index := range candidates {
+			values[i] = players[index].Value
+		}
+		for number, group := range clusterSorted(values, t.Count(position)) {
+			tier := Tier{Position: position, Number: number + 1, High: values[group[0]], Low: values[group[1]-1]}
+			tier.Players = append(tier.Players, candidates[group[0]:group[1]]...)
+			tiers = append(tiers, tier)
+		}
+	}
+	return tiers
+}
+
+// TierPositions lists the positions a player is tiered at: their eligible positions with the
+// outfield spots as OF, or UTIL (P for pitchers) when they have none
+func TierPositions(player RosterPlayer) []string {
+	var positions []string
+	for _, position := range player.Eligible {
+		if position == "LF" || position == "CF" || position == "RF" {
+			position = "OF"
+		}
+		if !slices.Contains(positions, position) {
+			positions = append(positions, position)
+		}
+	}
+	if len(positions) == 0 {
+		if player.Position == "pitcher" {
+			return []string{"P"}
+		}
+		return []string{"UTIL"}
+	}
+	return models.SortPositions(positions)
+}
+
+// clusterSorted splits values sorted high to low into at most k contiguous groups minimizing
+// the total squared distance from each group's mean, returned as [start, end) ranges
+func clusterSorted(values []float64, k int) [][2]int {
+	n := len(values)
+	k = min(k, n)
+	sums := make([]float64, n+1)
+	squares := make([]float64, n+1)
+	for i, value := range values {
+		sums[i+1] = sums[i] + value
+		squares[i+1] = squares[i] + value*value
+	}
+	cost := func(start, end int) float64 {
+		sum := sums[end] - sums[start]
+		return squares[end] - squares[start] - sum*sum/float64(end-start)
+	}
+
+	// best[g][end] is the least cost of splitting values[:end] into g+1 groups; starts[g][end]
+	// is where the last of those groups begins
+	best := make([][]float64, k)
+	starts := make([][]int, k)
+	for g := range best {
+		best[g] = make([]float64, n+1)
+		starts[g] = make([]int, n+1)
+		for end := 1; end <= n; end++ {
+			if g == 0 {
+				best[g][end] = cost(0, end)
+				continue
+			}
+			best[g][end] = math.Inf(1)
+			for start := g; start < end; start++ {
+				if total := best[g-1][start] + cost(start, end); total < best[g][end] {
+					best[g][end], starts[g][end] = total, start
+				}
+			}
+		}
+	}
+
+	groups := make([][2]int, k)
+	end := n
+	for g := k - 1; g >= 0; g-- {
+		groups[g] = [2]int{starts[g][end], end}
+		end = starts[g][end]
+	}
+	return groups
+}
@@ -0,0 +1,89 @@
+package baseball
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+func TestClusterSorted(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		k      int
+		want   [][2]int
+	}{
+		{name: "breaks at the gaps", values: []float64{50, 49, 48, 30, 29, 10}, k: 3, want: [][2]int{{0, 3}, {3, 5}, {5, 6}}},
+		{name: "one tier", values: []float64{50, 49, 10}, k: 1, want: [][2]int{{0, 3}}},
+		{name: "more tiers than players", values: []float64{5, 3}, k: 4, want: [][2]int{{0, 1}, {1, 2}}},
+		{name: "the widest gap wins", values: []float64{100, 98, 40, 30, 29}, k: 2, want: [][2]int{{0, 2}, {2, 5}}},
+		{name: "an outlier tiers alone", values: []float64{100, 60, 59, 58, 57}, k: 2, want: [][2]int{{0, 1}, {1, 5}}},
+		{name: "equal values split the same way every time", values: []float64{10, 10, 10}, k: 2, want: [][2]int{{0, 1}, {1, 3}}},
+		{name: "no players", values: nil, k: 3, want: [][2]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clusterSorted(tt.values, tt.k); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("clusterSorted = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTieringTiers(t *testing.T) {
+	tiering, err := NewTiering(models.TierSettings{Count: 2, Depth: 3, Positions: map[string]int{"OF": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	players := []RosterPlayer{
+		{Position: "batter", Eligible: []string{"C"}, Value: 100},
+		{Position: "batter", Eligible: []string{"C", "1B"}, Value: 95},
+		{Position: "batter", Eligible: []string{"C"}, Value: 60},
+		{Position: "batter", Eligible: []string{"C"}, Value: 55}, // past the depth
+		{Position: "batter", Eligible: []string{"LF", "RF"}, Value: 80},
+		{Position: "batter", Eligible: []string{"CF"}, Value: 70},
+	}
+	want := []Tier{
+		{Position: "C", Number: 1, Players: []int{0, 1}, High: 100, Low: 95},
+		{Position: "C", Number: 2, Players: []int{2}, High: 60, Low: 60},
+		{Position: "1B", Number: 1, Players: []int{1}, High: 95, Low: 95},
+		{Position: "OF", Number: 1, Players: []int{4, 5}, High: 80, Low: 70},
+	}
+	if got := tiering.Tiers(players); !reflect.DeepEqual(got, want) {
+		t.Errorf("Tiers = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestTierPositions(t *testing.T) {
+	tests := []struct {
+		player RosterPlayer
+		want   []string
+	}{
+		{RosterPlayer{Position: "batter", Eligible: []string{"LF", "CF", "DH"}}, []string{"OF", "DH"}},
+		{RosterPlayer{Position: "batter", Eligible: []string{"SS", "2B"}}, []string{"2B", "SS"}},
+		{RosterPlayer{Position: "batter"}, []string{"UTIL"}},
+		{RosterPlayer{Position: "pitcher", Eligible: []string{"SP"}}, []string{"SP"}},
+		{RosterPlayer{Position: "pitcher"}, []string{"P"}},
+	}
+	for _, tt := range tests {
+		if got := TierPositions(tt.player); !slices.Equal(got, tt.want) {
+			t.Errorf("TierPositions(%+v) = %v, want %v", tt.player, got, tt.want)
+		}
+	}
+}
+
+func TestNewTieringRejects(t *testing.T) {
+	for _, settings := range []models.TierSettings{
+		{Count: -1},
+		{Count: 21},
+		{Depth: -1},
+		{Positions: map[string]int{"LF": 2}},
+		{Positions: map[string]int{"C": 0}},
+	} {
+		if _, err := NewTiering(settings); err == nil {
+			t.Errorf("NewTiering(%+v) succeeded, want an error", settings)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// SaveADPCSV parses one site's ADP export and replaces the stored ADP for its format and year.
+// Rows are matched to the players the projections were, but players the registry doesn't know
+// aren't added: ADP lists run deep into prospects nobody projects, so those rows are stored
+// unlinked and listed as unmatched. Rows without an ADP are dropped. Dry runs stop after
+// building the report.
+func SaveADPCSV(csvData string, format baseball.ADPFormat, request models.UploadRequest) (models.UploadReport, error) {
+	columns, records, err := readMappedCSV(csvData, format.NewRow())
+	if err != nil {
+		return models.UploadReport{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	registry, err := LoadPlayerRegistry(ctx)
+	if err != nil {
+		return models.UploadReport{}, err
+	}
+
+	report := newUploadReport(len(records))
+	var documents []interface{}
+	var names []string
+	for i, record := range records {
+		row := format.NewRow()
+		addCellErrors(&report, i, columns.Decode(record, row))
+		entry := row.Entry()
+		if entry.Name == "" || entry.ADP <= 0 {
+			continue
+		}
+		if entry.Rank == 0 {
+			entry.Rank = i + 1
+		}
+		entry.Year = request.Year
+		entry.Source = format.Name
+
+		id, candidates := registry.Lookup(PlayerRow{
+			Name:     entry.Name,
+			Team:     entry.Team,
+			Position: baseball.ADPRole(entry.Positions),
+			Year:     entry.Year,
+			Source:   entry.Source,
+		})
+		if id == "" {
+			report.Unmatched = append(report.Unmatched, models.UnmatchedRow{
+				Row:        i + 2,
+				Name:       entry.Name,
+				Team:       entry.Team,
+				Candidates: candidates,
+			})
+		}
+		entry.PlayerID = id
+		documents = append(documents, entry)
+		names = append(names, entry.Name)
+	}
+	summarizeUpload(&report, names, documents, request.Preview)
+	if request.DryRun {
+		return report, nil
+	}
+	return report, replaceSlice(ctx, MongoInstance.ADP, bson.M{"source": format.Name, "year": request.Year}, documents)
+}
+
+// LoadADP reads the stored ADP for a format ("" for every format) and year. Without a year it
+// reads the latest one uploaded, which it returns.
+func LoadADP(ctx context.Context, source, year string) ([]models.ADPEntry, string, error) {
+	filter := bson.M{}
+	if source != "" {
+		filter["source"] = source
+	}
+	filter, year, err := VisibleYear(ctx, MongoInstance.ADP, filter, year)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cursor, err := MongoInstance.ADP.Find(ctx, filter)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query ADP: %v", err)
+	}
+	var entries []models.ADPEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, "", fmt.Errorf("failed to decode ADP: %v", err)
+	}
+	slices.SortStableFunc(entries, func(a, b models.ADPEntry) int {
+		if order := strings.Compare(a.Source, b.Source); order != 0 {
+			return order
+		}
+		return a.Rank - b.Rank
+	})
+	return entries, year, nil
+}
@@ -8,235 +8,150 @@ import (
 	"time"
 
 	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
 	"super-fantasy-api/utils"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-// SaveBatterCSV parses and saves generic batter CSV data to MongoDB
-func SaveFanGraphsBatterCSV(csvData string, year string, suffix string, position string) error {
-	reader := csv.NewReader(strings.NewReader(csvData))
-	records, err := reader.ReadAll()
+// defaultPreviewSize is how many parsed players an upload report shows when none is requested
+const defaultPreviewSize = 5
+
+// SaveProjectionCSV parses one source's batter or pitcher export and replaces the stored
+// slice for its year and position. Dry runs stop after building the report.
+func SaveProjectionCSV(csvData string, source baseball.ProjectionSource, request models.UploadRequest) (models.UploadReport, error) {
+	if !baseball.Supports(source, request.Position) {
+		return models.UploadReport{}, fmt.Errorf("%s has no %s projections", source.Name(), request.Position)
+	}
+	columns, records, err := readMappedCSV(csvData, source.NewRow(request.Position))
 	if err != nil {
-		return fmt.Errorf("failed to parse CSV: %v", err)
+		return models.UploadReport{}, err
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Optionally clear existing batter data with a specific source if needed
-	_, err = MongoInstance.Collection.DeleteMany(ctx, bson.M{"source": "batter-stats"})
+	registry, err := LoadPlayerRegistry(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to clear batter data: %v", err)
+		return models.UploadReport{}, err
 	}
+	meta := baseball.RowMeta{Year: request.Year, Source: source.Name(), Position: request.Position}
 
+	report := newUploadReport(len(records))
 	var documents []interface{}
+	var names []string
 	for i, record := range records {
-		if i == 0 {
-			continue // Skip header
-		}
-		player := baseball.FangraphsBatter{
-			Rank:           utils.ParseInt(record[0]),
-			Name:           record[1],
-			Team:           record[2],
-			Games:          utils.ParseFloat(record[3]),
-			AtBats:         utils.ParseFloat(record[4]),
-			PlateApps:      utils.ParseFloat(record[5]),
-			Hits:           utils.ParseFloat(record[6]),
-			Singles:        utils.ParseFloat(record[7]),
-			Doubles:        utils.ParseFloat(record[8]),
-			Triples:        utils.ParseFloat(record[9]),
-			HomeRuns:       utils.ParseFloat(record[10]),
-			Runs:           utils.ParseFloat(record[11]),
-			RBI:            utils.ParseFloat(record[12]),
-			Walks:          utils.ParseFloat(record[13]),
-			IntWalks:       utils.ParseFloat(record[14]),
-			Strikeouts:     utils.ParseFloat(record[15]),
-			HitByPitch:     utils.ParseFloat(record[16]),
-			SacFlies:       utils.ParseFloat(record[17]),
-			SacHits:        utils.ParseFloat(record[18]),
-			StolenBases:    utils.ParseFloat(record[19]),
-			CaughtStealing: utils.ParseFloat(record[20]),
-			AVG:            utils.ParseFloat(record[21]),
-			Year:           year,
-			Source:         "fangraphs",
-			Position:       position,
-		}
-		if suffix != "" {
-			player.Source += "_" + suffix
-		}
-		documents = append(documents, player)
-	}
-
-	_, err = MongoInstance.Collection.InsertMany(ctx, documents)
-	if err != nil {
-		return fmt.Errorf("failed to insert documents: %v", err)
-	}
-	return nil
+		row := source.NewRow(request.Position)
+		addCellErrors(&report, i, baseball.DecodeRow(columns, record, row, meta))
+		line := row.Line()
+		row.Link(matchPlayer(registry, &report, i, PlayerRow{
+			Name:      line.Name,
+			Team:      line.Team,
+			Position:  line.Position,
+			Year:      line.Year,
+			Source:    line.Source,
+			IDs:       line.IDs,
+			Positions: models.ParsePositions(line.Positions),
+		}))
+		documents = append(documents, row)
+		names = append(names, line.Name)
+	}
+	summarizeUpload(&report, names, documents, request.Preview)
+	if request.DryRun {
+		return report, nil
+	}
+
+	if err := registry.Save(ctx); err != nil {
+		return report, err
+	}
+	return report, replaceSlice(ctx, MongoInstance.Collection, bson.M{"source": source.Name(), "year": request.Year, "position": request.Position}, documents)
 }
 
-// SavePitcherCSV parses and saves pitcher CSV data to MongoDB
-func SaveFanGraphsPitcherCSV(csvData string, year string, suffix string, position string) error {
-	reader := csv.NewReader(strings.NewReader(csvData))
-	records, err := reader.ReadAll()
-	if err != nil {
-		return fmt.Errorf("failed to parse CSV: %v", err)
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	_, err = MongoInstance.Collection.DeleteMany(ctx, bson.M{"source": "pitcher-stats"})
-	if err != nil {
-		return fmt.Errorf("failed to clear pitcher data: %v", err)
+func newUploadReport(rows int) models.UploadReport {
+	return models.UploadReport{
+		Rows:       rows,
+		Errors:     []models.RowError{},
+		BlankCells: []models.BlankColumn{},
+		Duplicates: []string{},
+		Unmatched:  []models.UnmatchedRow{},
 	}
+}
 
-	var documents []interface{}
-	for i, record := range records {
-		if i == 0 {
-			continue // Skip header
-		}
-		player := baseball.FangraphsPitcher{
-			Rank:              utils.ParseInt(record[0]),    // #
-			Name:              record[1],                    // Name
-			Team:              record[2],                    // Team
-			Wins:              utils.ParseFloat(record[3]),  // W
-			Losses:            utils.ParseFloat(record[4]),  // L
-			ERA:               utils.ParseFloat(record[5]),  // ERA
-			Games:             utils.ParseFloat(record[6]),  // G
-			GamesStarted:      utils.ParseFloat(record[7]),  // GS
-			Saves:             utils.ParseFloat(record[8]),  // SV
-			Holds:             utils.ParseFloat(record[9]),  // HLD
-			BlownSaves:        utils.ParseFloat(record[10]), // BS
-			InningsPitched:    utils.ParseFloat(record[11]), // IP
-			TotalBattersFaced: utils.ParseFloat(record[12]), // TBF
-			HitsAllowed:       utils.ParseFloat(record[13]), // H
-			RunsAllowed:       utils.ParseFloat(record[14]), // R
-			EarnedRuns:        utils.ParseFloat(record[15]), // ER
-			HomeRunsAllowed:   utils.ParseFloat(record[16]), // HR
-			Walks:             utils.ParseFloat(record[17]), // BB
-			IntWalks:          utils.ParseFloat(record[18]), // IBB
-			HitByPitch:        utils.ParseFloat(record[19]), // HBP
-			Strikeouts:        utils.ParseFloat(record[20]), // SO
-			Year:              year,
-			Source:            "fangraphs",
-			Position:          position,
-		}
-		if suffix != "" {
-			player.Source += "_" + suffix
+// addCellErrors files the problem cells of one data row into the report. Blank cells are
+// counted per column, since some exports (e.g. Steamer's BS) leave a column empty throughout.
+func addCellErrors(report *models.UploadReport, index int, cells []utils.CellError) {
+	for _, cell := range cells {
+		if cell.Blank {
+			addBlankCell(report, cell.Column)
+			continue
 		}
-		documents = append(documents, player)
-	}
-
-	_, err = MongoInstance.Collection.InsertMany(ctx, documents)
-	if err != nil {
-		return fmt.Errorf("failed to insert documents: %v", err)
+		report.Errors = append(report.Errors, models.RowError{
+			Row:    index + 2, // header is row 1
+			Column: cell.Column,
+			Value:  cell.Value,
+		})
 	}
-	return nil
 }
 
-// SaveFantasyProsBatterCSV saves FantasyPros batter CSV data to MongoDB
-func SaveFantasyProsBatterCSV(csvData string, year string, position string) error {
-	reader := csv.NewReader(strings.NewReader(csvData))
-	records, err := reader.ReadAll()
-	if err != nil {
-		return fmt.Errorf("failed to parse CSV: %v", err)
+func addBlankCell(report *models.UploadReport, column string) {
+	for i := range report.BlankCells {
+		if report.BlankCells[i].Column == column {
+			report.BlankCells[i].Count++
+			return
+		}
 	}
+	report.BlankCells = append(report.BlankCells, models.BlankColumn{Column: column, Count: 1})
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	_, err = MongoInstance.Collection.DeleteMany(ctx, bson.M{"source": "fantasypros-batter"})
-	if err != nil {
-		return fmt.Errorf("failed to clear FantasyPros batter data: %v", err)
-	}
+// matchPlayer links a row to the registry, listing it in the report when it stays ambiguous
+func matchPlayer(registry *PlayerRegistry, report *models.UploadReport, index int, row PlayerRow) string {
+	id, candidates := registry.Match(row)
+	if id == "" {
+		report.Unmatched = append(report.Unmatched, models.UnmatchedRow{
+			Row:        index + 2,
+			Name:       row.Name,
+			Team:       row.Team,
+			Candidates: candidates,
+		})
+	}
+	return id
+}
 
-	var documents []interface{}
-	for i, record := range records {
-		if i == 0 {
-			continue // Skip header
-		}
-		player := baseball.FantasyProsBatter{
-			Name:        record[0],
-			Team:        record[1],
-			Positions:   record[2],
-			AtBats:      utils.ParseFloat(record[3]),
-			Runs:        utils.ParseFloat(record[4]),
-			HomeRuns:    utils.ParseFloat(record[5]),
-			RBI:         utils.ParseFloat(record[6]),
-			StolenBases: utils.ParseFloat(record[7]),
-			AVG:         utils.ParseFloat(record[8]),
-			OBP:         utils.ParseFloat(record[9]),
-			Hits:        utils.ParseFloat(record[10]),
-			Doubles:     utils.ParseFloat(record[11]),
-			Triples:     utils.ParseFloat(record[12]),
-			Walks:       utils.ParseFloat(record[13]),
-			Strikeouts:  utils.ParseFloat(record[14]),
-			SLG:         utils.ParseFloat(record[15]),
-			OPS:         utils.ParseFloat(record[16]),
-			Year:        year,
-			Source:      "fantasypros",
-			Position:    position,
+// summarizeUpload adds the duplicate player names and the preview of parsed players to the report
+func summarizeUpload(report *models.UploadReport, names []string, documents []interface{}, preview int) {
+	seen := make(map[string]int)
+	for _, name := range names {
+		key := utils.NormalizeName(name)
+		seen[key]++
+		if seen[key] == 2 {
+			report.Duplicates = append(report.Duplicates, name)
 		}
-		documents = append(documents, player)
 	}
 
-	_, err = MongoInstance.Collection.InsertMany(ctx, documents)
-	if err != nil {
-		return fmt.Errorf("failed to insert documents: %v", err)
+	if preview <= 0 {
+		preview = defaultPreviewSize
+	}
+	if preview > len(documents) {
+		preview = len(documents)
 	}
-	return nil
+	report.Preview = documents[:preview]
 }
 
-// SaveFantasyProsPitcherCSV saves FantasyPros pitcher CSV data to MongoDB
-func SaveFantasyProsPitcherCSV(csvData string, year string, position string) error {
+// readMappedCSV parses csvData and maps its header row onto the csv tags of target,
+// returning the column map and the data rows that follow the header
+func readMappedCSV(csvData string, target interface{}) (*utils.ColumnMap, [][]string, error) {
 	reader := csv.NewReader(strings.NewReader(csvData))
 	records, err := reader.ReadAll()
 	if err != nil {
-		return fmt.Errorf("failed to parse CSV: %v", err)
+		return nil, nil, fmt.Errorf("failed to parse CSV: %v", err)
 	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	_, err = MongoInstance.Collection.DeleteMany(ctx, bson.M{"source": "fantasypros-pitcher"})
-	if err != nil {
-		return fmt.Errorf("failed to clear FantasyPros pitcher data: %v", err)
-	}
-
-	var documents []interface{}
-	for i, record := range records {
-		if i == 0 {
-			continue // Skip header
-		}
-		player := baseball.FantasyProsPitcher{
-			Name:            record[0],
-			Team:            record[1],
-			Positions:       record[2],
-			InningsPitched:  utils.ParseFloat(record[3]),
-			Strikeouts:      utils.ParseFloat(record[4]),
-			Wins:            utils.ParseFloat(record[5]),
-			Saves:           utils.ParseFloat(record[6]),
-			ERA:             utils.ParseFloat(record[7]),
-			WHIP:            utils.ParseFloat(record[8]),
-			EarnedRuns:      utils.ParseFloat(record[9]),
-			HitsAllowed:     utils.ParseFloat(record[10]),
-			Walks:           utils.ParseFloat(record[11]),
-			HomeRunsAllowed: utils.ParseFloat(record[12]),
-			Games:           utils.ParseFloat(record[13]),
-			GamesStarted:    utils.ParseFloat(record[14]),
-			Losses:          utils.ParseFloat(record[15]),
-			CompleteGames:   utils.ParseFloat(record[16]),
-			Year:            year,
-			Source:          "fantasypros",
-			Position:        position,
-		}
-		documents = append(documents, player)
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("CSV has no header row")
 	}
 
-	_, err = MongoInstance.Collection.InsertMany(ctx, documents)
+	columns, err := utils.MapColumns(records[0], target)
 	if err != nil {
-		return fmt.Errorf("failed to insert documents: %v", err)
+		return nil, nil, fmt.Errorf("failed to map CSV header: %w", err)
 	}
-	return nil
+	return columns, records[1:], nil
 }
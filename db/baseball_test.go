@@ -0,0 +1,39 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+)
+
+func TestUploadReport(t *testing.T) {
+	report := newUploadReport(4)
+	addCellErrors(&report, 0, []utils.CellError{{Column: "BS", Blank: true}, {Column: "HR", Value: "3O"}})
+	addCellErrors(&report, 1, []utils.CellError{{Column: "BS", Blank: true}})
+	addCellErrors(&report, 2, nil)
+	addCellErrors(&report, 3, []utils.CellError{{Column: "BS", Blank: true}, {Column: "Team", Blank: true}})
+
+	// Rows are numbered as in the file, after the header
+	if want := []models.RowError{{Row: 2, Column: "HR", Value: "3O"}}; !reflect.DeepEqual(report.Errors, want) {
+		t.Errorf("Errors = %+v, want %+v", report.Errors, want)
+	}
+	if want := []models.BlankColumn{{Column: "BS", Count: 3}, {Column: "Team", Count: 1}}; !reflect.DeepEqual(report.BlankCells, want) {
+		t.Errorf("BlankCells = %+v, want %+v", report.BlankCells, want)
+	}
+
+	names := []string{"José Ramírez", "Aaron Judge", "Jose Ramirez", "Will Smith", "Will Smith", "Will Smith"}
+	documents := []interface{}{1, 2, 3, 4, 5, 6}
+	summarizeUpload(&report, names, documents, 0)
+	if want := []string{"Jose Ramirez", "Will Smith"}; !reflect.DeepEqual(report.Duplicates, want) {
+		t.Errorf("Duplicates = %v, want each repeated name once", report.Duplicates)
+	}
+	if len(report.Preview) != defaultPreviewSize {
+		t.Errorf("default preview has %d players, want %d", len(report.Preview), defaultPreviewSize)
+	}
+	summarizeUpload(&report, nil, documents[:2], 3)
+	if !reflect.DeepEqual(report.Preview, documents[:2]) {
+		t.Errorf("Preview = %v, want every player when there are fewer than asked for", report.Preview)
+	}
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sliceBatches is the Batches document for one slice of an upload collection: the batches of
+// the slice readers skip, because they're still being inserted or have been replaced
+type sliceBatches struct {
+	ID         string   `bson:"_id"` // the collection's name and the slice's fields
+	Collection string   `bson:"collection"`
+	Hidden     []string `bson:"hidden"`
+}
+
+// replaceSlice swaps a collection's stored documents for one slice (source, year and position
+// for projections) with a new upload. The new rows are inserted under a fresh batch that
+// readers skip (see Visible) until every insert has succeeded; a single write to the slice's
+// Batches document then hides the previous rows and shows the new ones, so readers never see
+// both or neither. The previous rows are deleted afterwards. A failed upload leaves the
+// existing slice as it was.
+func replaceSlice(ctx context.Context, collection *mongo.Collection, slice bson.M, documents []interface{}) error {
+	if len(documents) == 0 {
+		return fmt.Errorf("CSV has no player rows")
+	}
+
+	batch := primitive.NewObjectID().Hex()
+	staged := make([]interface{}, 0, len(documents))
+	for _, document := range documents {
+		raw, err := bson.Marshal(document)
+		if err != nil {
+			return fmt.Errorf("failed to encode document: %v", err)
+		}
+		var doc bson.D
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			return fmt.Errorf("failed to encode document: %v", err)
+		}
+		staged = append(staged, append(doc, bson.E{Key: "batch", Value: batch}))
+	}
+
+	// Rows saved before batches existed join a batch of their own, so they can be hidden too
+	legacy := withSlice(slice, bson.M{"batch": bson.M{"$exists": false}})
+	if _, err := collection.UpdateMany(ctx, legacy, bson.M{"$set": bson.M{"batch": primitive.NewObjectID().Hex()}}); err != nil {
+		return fmt.Errorf("failed to prepare previous upload: %v", err)
+	}
+
+	key := sliceKey(collection, slice)
+	if _, err := MongoInstance.Batches.UpdateOne(ctx,
+		bson.M{"_id": key},
+		bson.M{"$addToSet": bson.M{"hidden": batch}, "$set": bson.M{"collection": collection.Name()}},
+		options.Update().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to stage upload: %v", err)
+	}
+
+	if _, err := collection.InsertMany(ctx, staged); err != nil {
+		// Roll back whatever part of the batch made it in; ctx may already be spent. Rows the
+		// rollback misses stay hidden.
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, cleanupErr := collection.DeleteMany(cleanupCtx, bson.M{"batch": batch}); cleanupErr != nil {
+			return fmt.Errorf("failed to insert documents: %v (and failed to remove the partial upload: %v)", err, cleanupErr)
+		}
+		if cleanupErr := unhide(cleanupCtx, key, []string{batch}); cleanupErr != nil {
+			return fmt.Errorf("failed to insert documents: %v (and %v)", err, cleanupErr)
+		}
+		return fmt.Errorf("failed to insert documents: %v", err)
+	}
+
+	values, err := collection.Distinct(ctx, "batch", withSlice(slice, bson.M{"batch": bson.M{"$ne": batch}}))
+	if err != nil {
+		return fmt.Errorf("failed to find previous upload: %v", err)
+	}
+	previous := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			previous = append(previous, id)
+		}
+	}
+
+	// The switch-over: one update, so readers go straight from the previous rows to the new ones
+	switchOver := bson.A{bson.M{"$set": bson.M{"hidden": bson.M{"$setUnion": bson.A{
+		bson.M{"$setDifference": bson.A{bson.M{"$ifNull": bson.A{"$hidden", bson.A{}}}, bson.A{batch}}},
+		previous,
+	}}}}}
+	if _, err := MongoInstance.Batches.UpdateOne(ctx, bson.M{"_id": key}, switchOver); err != nil {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, cleanupErr := collection.DeleteMany(cleanupCtx, bson.M{"batch": batch}); cleanupErr != nil {
+			return fmt.Errorf("failed to switch to the new upload: %v (and failed to remove it: %v)", err, cleanupErr)
+		}
+		return fmt.Errorf("failed to switch to the new upload: %v", err)
+	}
+
+	if len(previous) == 0 {
+		return nil
+	}
+	if _, err := collection.DeleteMany(ctx, withSlice(slice, bson.M{"batch": bson.M{"$in": previous}})); err != nil {
+		return fmt.Errorf("upload saved, but failed to remove the previous one, which stays hidden: %v", err)
+	}
+	if err := unhide(ctx, key, previous); err != nil {
+		return fmt.Errorf("upload saved, but %v", err)
+	}
+	return nil
+}
+
+// Visible narrows a query on an upload collection to the rows readers should see, leaving out
+// uploads that are still being inserted and those that have been replaced
+func Visible(ctx context.Context, collection *mongo.Collection, filter bson.M) (bson.M, error) {
+	cursor, err := MongoInstance.Batches.Find(ctx, bson.M{"collection": collection.Name(), "hidden.0": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query upload batches: %v", err)
+	}
+	var batches []sliceBatches
+	if err := cursor.All(ctx, &batches); err != nil {
+		return nil, fmt.Errorf("failed to decode upload batches: %v", err)
+	}
+
+	visible := bson.M{}
+	for key, value := range filter {
+		visible[key] = value
+	}
+	var hidden []string
+	for _, slice := range batches {
+		hidden = append(hidden, slice.Hidden...)
+	}
+	if len(hidden) > 0 {
+		visible["batch"] = bson.M{"$nin": hidden}
+	}
+	return visible, nil
+}
+
+// VisibleYear narrows a Visible query to one year's uploads, by default the latest year the
+// filter finds, which it returns
+func VisibleYear(ctx context.Context, collection *mongo.Collection, filter bson.M, year string) (bson.M, string, error) {
+	visible, err := Visible(ctx, collection, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if year == "" {
+		years, err := collection.Distinct(ctx, "year", visible)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to query uploaded years: %v", err)
+		}
+		for _, value := range years {
+			if uploaded, ok := value.(string); ok && uploaded > year {
+				year = uploaded
+			}
+		}
+	}
+	visible["year"] = year
+	return visible, year, nil
+}
+
+// unhide forgets hidden batches whose rows are gone
+func unhide(ctx context.Context, key string, batches []string) error {
+	if _, err := MongoInstance.Batches.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$pull": bson.M{"hidden": bson.M{"$in": batches}}}); err != nil {
+		return fmt.Errorf("failed to update upload batches: %v", err)
+	}
+	return nil
+}
+
+// sliceKey names a slice of a collection: the collection, then the slice's fields in order
+// ("projections|position=batter|source=fantasypros|year=2026")
+func sliceKey(collection *mongo.Collection, slice bson.M) string {
+	fields := make([]string, 0, len(slice))
+	for key, value := range slice {
+		fields = append(fields, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(fields)
+	return strings.Join(append([]string{collection.Name()}, fields...), "|")
+}
+
+// withSlice adds a slice's fields to a filter
+func withSlice(slice bson.M, filter bson.M) bson.M {
+	for key, value := range slice {
+		filter[key] = value
+	}
+	return filter
+}
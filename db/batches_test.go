@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestSliceKey(t *testing.T) {
+	// The client never connects; collections only need it for their names
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Disconnect(context.Background())
+	projections := client.Database("test").Collection("projections")
+	adp := client.Database("test").Collection("adp")
+
+	slice := bson.M{"source": "fantasypros", "year": "2026", "position": "batter"}
+	if got, want := sliceKey(projections, slice), "projections|position=batter|source=fantasypros|year=2026"; got != want {
+		t.Errorf("sliceKey = %q, want %q", got, want)
+	}
+	// The same fields name different slices in different collections, whatever their order
+	reordered := bson.M{"position": "batter", "year": "2026", "source": "fantasypros"}
+	if sliceKey(projections, reordered) != sliceKey(projections, slice) {
+		t.Error("field order changed the key")
+	}
+	if sliceKey(adp, slice) == sliceKey(projections, slice) {
+		t.Error("two collections share a slice key")
+	}
+	if sliceKey(projections, bson.M{"source": "fantasypros", "year": "2025", "position": "batter"}) == sliceKey(projections, slice) {
+		t.Error("two years share a slice key")
+	}
+}
+
+func TestWithSlice(t *testing.T) {
+	slice := bson.M{"source": "nfbc", "year": "2026"}
+	filter := withSlice(slice, bson.M{"batch": bson.M{"$exists": false}})
+	if len(filter) != 3 || filter["source"] != "nfbc" || filter["year"] != "2026" || filter["batch"] == nil {
+		t.Errorf("withSlice = %v, want the slice's fields and the batch condition", filter)
+	}
+	if len(slice) != 2 {
+		t.Errorf("withSlice changed the slice to %v", slice)
+	}
+}
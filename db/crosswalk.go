@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+)
+
+// ImportCrosswalk loads a player ID map (SFBB Player ID Map, Chadwick register, ...) into the
+// registry. Each row is linked to an existing player by one of its IDs, or by name when the
+// player's team or role agrees, and its MLBAM, FanGraphs and FantasyPros IDs are attached so
+// later uploads and exports can match on IDs instead of names. No players are created.
+func ImportCrosswalk(csvData string) (models.CrosswalkReport, error) {
+	columns, records, err := readMappedCSV(csvData, baseball.CrosswalkRow{})
+	if err != nil {
+		return models.CrosswalkReport{}, err
+	}
+	if err := crosswalkColumns(columns); err != nil {
+		return models.CrosswalkReport{}, fmt.Errorf("failed to map CSV header: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	registry, err := LoadPlayerRegistry(ctx)
+	if err != nil {
+		return models.CrosswalkReport{}, err
+	}
+
+	report := models.CrosswalkReport{Rows: len(records), Unmatched: []models.UnmatchedRow{}}
+	for i, record := range records {
+		var row baseball.CrosswalkRow
+		columns.Decode(record, &row)
+		name, ids := row.FullName(), row.IDs()
+		if ids == (models.PlayerIDs{}) {
+			report.Skipped++
+			continue
+		}
+
+		player, named := registry.confirm(PlayerRow{Name: name, Team: models.CanonicalTeam(row.Team), Position: row.Role(), IDs: ids})
+		switch {
+		case player == nil && len(named) == 0:
+			report.NotFound++
+			continue
+		case player == nil:
+			candidates := make([]models.Player, 0, len(named))
+			for _, p := range named {
+				candidates = append(candidates, *p)
+			}
+			report.Unmatched = append(report.Unmatched, models.UnmatchedRow{Row: i + 2, Name: name, Team: row.Team, Candidates: candidates})
+			continue
+		}
+		report.Matched++
+		if registry.attachIDs(player, ids) {
+			registry.changed[player.ID] = true
+		}
+
+		// FantasyPros sometimes spells names differently; remember its spelling
+		if row.FantasyProsName != "" && utils.NameKey(row.FantasyProsName) != utils.NameKey(name) {
+			registry.AddAlias(models.PlayerAlias{Name: row.FantasyProsName, Source: "fantasypros", PlayerID: player.ID})
+		}
+	}
+
+	if err := registry.Save(ctx); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// crosswalkColumns checks that a crosswalk's header has an ID column to import; a row can
+// only be linked by name when the file also has a name column
+func crosswalkColumns(columns *utils.ColumnMap) error {
+	absent := columns.Absent()
+	for _, column := range []string{"MLBID", "IDFANGRAPHS", "FANTPROSID"} {
+		if !slices.Contains(absent, column) {
+			return nil
+		}
+	}
+	missing := []string{"MLBID", "IDFANGRAPHS", "FANTPROSID"}
+	if slices.Contains(absent, "PLAYERNAME") && (slices.Contains(absent, "FIRSTNAME") || slices.Contains(absent, "LASTNAME")) {
+		missing = append([]string{"PLAYERNAME"}, missing...)
+	}
+	return &utils.MissingColumnsError{Missing: missing}
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+)
+
+// testRegistry is a registry holding players, as LoadPlayerRegistry would read them
+func testRegistry(players ...models.Player) *PlayerRegistry {
+	registry := &PlayerRegistry{
+		players: make(map[string]*models.Player),
+		byName:  make(map[string][]string),
+		byID:    make(map[string]string),
+		aliases: make(map[string]string),
+		claimed: make(map[string]bool),
+		changed: make(map[string]bool),
+	}
+	for i := range players {
+		players[i].NameKey = utils.NameKey(players[i].Name)
+		registry.add(&players[i])
+	}
+	return registry
+}
+
+func TestRegistryConfirm(t *testing.T) {
+	registry := testRegistry(
+		models.Player{ID: "catcher", Name: "Will Smith", Team: "LAD", Roles: []string{"batter"}},
+		models.Player{ID: "reliever", Name: "Will Smith", Team: "TEX", Roles: []string{"pitcher"}},
+		models.Player{ID: "judge", Name: "Aaron Judge", Team: "NYY", Roles: []string{"batter"}, PlayerIDs: models.PlayerIDs{MLBAM: "592450"}},
+		models.Player{ID: "rookie", Name: "Max Muncy", Team: "ATH", Roles: []string{"batter"}},
+	)
+
+	tests := []struct {
+		name      string
+		row       PlayerRow
+		want      string
+		unmatched int
+	}{
+		{"external ID", PlayerRow{Name: "A. Judge", IDs: models.PlayerIDs{MLBAM: "592450", FanGraphs: "15640"}}, "judge", 0},
+		{"external ID without a name", PlayerRow{IDs: models.PlayerIDs{MLBAM: "592450"}}, "judge", 0},
+		{"name and team", PlayerRow{Name: "Will Smith", Team: "LAD", IDs: models.PlayerIDs{MLBAM: "669257"}}, "catcher", 0},
+		{"name and role", PlayerRow{Name: "Will Smith", Team: "KC", Position: "pitcher", IDs: models.PlayerIDs{MLBAM: "519293"}}, "reliever", 0},
+		{"name alone", PlayerRow{Name: "Will Smith", IDs: models.PlayerIDs{MLBAM: "1"}}, "", 2},
+		{"name with another team", PlayerRow{Name: "Max Muncy", Team: "LAD", IDs: models.PlayerIDs{MLBAM: "571970"}}, "", 1},
+		{"name with a different ID", PlayerRow{Name: "Aaron Judge", Team: "NYY", IDs: models.PlayerIDs{MLBAM: "999"}}, "", 1},
+		{"unknown player", PlayerRow{Name: "Nobody Here", Team: "NYY", IDs: models.PlayerIDs{MLBAM: "2"}}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player, named := registry.confirm(tt.row)
+			got := ""
+			if player != nil {
+				got = player.ID
+			}
+			if got != tt.want || len(named) != tt.unmatched {
+				t.Errorf("confirm = %q with %d candidates, want %q with %d", got, len(named), tt.want, tt.unmatched)
+			}
+		})
+	}
+}
+
+func TestCrosswalkColumns(t *testing.T) {
+	tests := []struct {
+		header  []string
+		missing []string
+	}{
+		{[]string{"PLAYERNAME", "TEAM", "MLBID", "IDFANGRAPHS"}, nil},
+		{[]string{"key_mlbam", "key_fangraphs"}, nil},
+		{[]string{"name_first", "name_last", "key_fangraphs"}, nil},
+		{[]string{"PLAYERNAME", "TEAM"}, []string{"MLBID", "IDFANGRAPHS", "FANTPROSID"}},
+		{[]string{"TEAM", "POS"}, []string{"PLAYERNAME", "MLBID", "IDFANGRAPHS", "FANTPROSID"}},
+	}
+	for _, tt := range tests {
+		columns, err := utils.MapColumns(tt.header, baseball.CrosswalkRow{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = crosswalkColumns(columns)
+		var missing *utils.MissingColumnsError
+		if tt.missing == nil {
+			if err != nil {
+				t.Errorf("%v: %v", tt.header, err)
+			}
+			continue
+		}
+		if !errors.As(err, &missing) || !slices.Equal(missing.Missing, tt.missing) {
+			t.Errorf("%v: err = %v, want missing %v", tt.header, err, tt.missing)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"super-fantasy-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrDraftNotFound is returned when a draft ID isn't saved
+var ErrDraftNotFound = errors.New("draft not found")
+
+// ErrDraftChanged is returned when a draft's picks were saved by someone else since they were read
+var ErrDraftChanged = errors.New("draft changed")
+
+// CreateDraft saves a new draft under a fresh ID
+func CreateDraft(draft models.Draft) (models.Draft, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	draft.ID = primitive.NewObjectID().Hex()
+	// Mongo keeps milliseconds, and SaveDraftPicks matches on the exact updated_at
+	draft.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	draft.UpdatedAt = draft.CreatedAt
+	if draft.Picks == nil {
+		draft.Picks = []models.DraftPick{}
+	}
+	if _, err := MongoInstance.Drafts.InsertOne(ctx, draft); err != nil {
+		return models.Draft{}, fmt.Errorf("failed to save draft: %v", err)
+	}
+	return draft, nil
+}
+
+// GetDraft loads one draft with its picks
+func GetDraft(id string) (models.Draft, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var draft models.Draft
+	if err := MongoInstance.Drafts.FindOne(ctx, bson.M{"_id": id}).Decode(&draft); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Draft{}, fmt.Errorf("%w: %s", ErrDraftNotFound, id)
+		}
+		return models.Draft{}, fmt.Errorf("failed to load draft: %v", err)
+	}
+	if draft.Picks == nil {
+		draft.Picks = []models.DraftPick{}
+	}
+	return draft, nil
+}
+
+// SaveDraftPicks replaces a draft's picks, returning when it was updated. since is when the
+// draft the picks were made from was last updated; if it has been saved since, nothing is
+// written and ErrDraftChanged is returned.
+func SaveDraftPicks(id string, since time.Time, picks []models.DraftPick) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	updated := time.Now().UTC().Truncate(time.Millisecond)
+	result, err := MongoInstance.Drafts.UpdateOne(ctx,
+		bson.M{"_id": id, "updated_at": since},
+		bson.M{"$set": bson.M{"picks": picks, "updated_at": updated}})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to save picks: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		count, err := MongoInstance.Drafts.CountDocuments(ctx, bson.M{"_id": id})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to save picks: %v", err)
+		}
+		if count == 0 {
+			return time.Time{}, fmt.Errorf("%w: %s", ErrDraftNotFound, id)
+		}
+		return time.Time{}, fmt.Errorf("%w: %s", ErrDraftChanged, id)
+	}
+	return updated, nil
+}
+
+// DeleteDraft removes a draft and its picks
+func DeleteDraft(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := MongoInstance.Drafts.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete draft: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w: %s", ErrDraftNotFound, id)
+	}
+	return nil
+}
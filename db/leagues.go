@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"super-fantasy-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrLeagueNotFound is returned when a league ID isn't saved
+var ErrLeagueNotFound = errors.New("league not found")
+
+// CreateLeague saves a new league profile under a fresh ID
+func CreateLeague(league models.League) (models.League, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	league.ID = primitive.NewObjectID().Hex()
+	league.CreatedAt = time.Now().UTC()
+	league.UpdatedAt = league.CreatedAt
+	if _, err := MongoInstance.Leagues.InsertOne(ctx, league); err != nil {
+		return models.League{}, fmt.Errorf("failed to save league: %v", err)
+	}
+	return league, nil
+}
+
+// GetLeague loads one league profile
+func GetLeague(id string) (models.League, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var league models.League
+	if err := MongoInstance.Leagues.FindOne(ctx, bson.M{"_id": id}).Decode(&league); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.League{}, fmt.Errorf("%w: %s", ErrLeagueNotFound, id)
+		}
+		return models.League{}, fmt.Errorf("failed to load league: %v", err)
+	}
+	return league, nil
+}
+
+// ListLeagues returns every saved league, by name
+func ListLeagues() ([]models.League, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := MongoInstance.Leagues.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leagues: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	leagues := []models.League{}
+	if err := cursor.All(ctx, &leagues); err != nil {
+		return nil, fmt.Errorf("failed to decode leagues: %v", err)
+	}
+	return leagues, nil
+}
+
+// UpdateLeague replaces a saved league's profile, keeping its ID and creation time
+func UpdateLeague(id string, league models.League) (models.League, error) {
+	existing, err := GetLeague(id)
+	if err != nil {
+		return models.League{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	league.ID = id
+	league.CreatedAt = existing.CreatedAt
+	league.UpdatedAt = time.Now().UTC()
+	if _, err := MongoInstance.Leagues.ReplaceOne(ctx, bson.M{"_id": id}, league); err != nil {
+		return models.League{}, fmt.Errorf("failed to update league: %v", err)
+	}
+	return league, nil
+}
+
+// DeleteLeague removes a saved league
+func DeleteLeague(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := MongoInstance.Leagues.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete league: %v", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("%w: %s", ErrLeagueNotFound, id)
+	}
+	return nil
+}
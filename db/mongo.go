@@ -16,6 +16,12 @@ type MongoDB struct {
 	Client     *mongo.Client
 	Database   *mongo.Database
 	Collection *mongo.Collection
+	Players    *mongo.Collection // canonical player registry
+	Aliases    *mongo.Collection // source names linked to registry players
+	Leagues    *mongo.Collection // saved league profiles
+	Drafts     *mongo.Collection // drafts and their picks
+	ADP        *mongo.Collection // uploaded average draft positions
+	Batches    *mongo.Collection // upload batches readers skip, per slice
 }
 
 // InitMongoDB initializes the MongoDB connection
@@ -45,5 +51,11 @@ func NewMongoDB(uri, dbName, collectionName string) (*MongoDB, error) {
 		Client:     client,
 		Database:   database,
 		Collection: collection,
+		Players:    database.Collection("players"),
+		Aliases:    database.Collection("player_aliases"),
+		Leagues:    database.Collection("leagues"),
+		Drafts:     database.Collection("drafts"),
+		ADP:        database.Collection("adp"),
+		Batches:    database.Collection("upload_batches"),
 	}, nil
 }
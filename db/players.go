@@ -0,0 +1,455 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrPlayerNotFound is returned when a player ID isn't in the registry
+var ErrPlayerNotFound = errors.New("player not found")
+
+// PlayerRow identifies one uploaded projection row for matching against the registry
+type PlayerRow struct {
+	Name     string
+	Team     string
+	Position string // "batter" or "pitcher"; empty when unknown
+	Year     string
+	Source   string
+	IDs      models.PlayerIDs
+	// Positions the row lists the player as eligible at, e.g. FantasyPros' "RF,DH"
+	Positions []string
+}
+
+// PlayerRegistry is an in-memory copy of the players and aliases collections used to link
+// the rows of one upload to canonical players. Changes are kept until Save is called.
+type PlayerRegistry struct {
+	players    map[string]*models.Player // by ID
+	byName     map[string][]string       // name key -> player IDs
+	byID       map[string]string         // external ID ("mlbam:660271") -> player ID
+	aliases    map[string]string         // source + "|" + name key -> player ID
+	claimed    map[string]bool           // players already linked to a row of this upload
+	changed    map[string]bool           // players created or updated since loading
+	newAliases []models.PlayerAlias      // aliases added since loading
+}
+
+// LoadPlayerRegistry reads the player registry and alias table
+func LoadPlayerRegistry(ctx context.Context) (*PlayerRegistry, error) {
+	registry := &PlayerRegistry{
+		players: make(map[string]*models.Player),
+		byName:  make(map[string][]string),
+		byID:    make(map[string]string),
+		aliases: make(map[string]string),
+		claimed: make(map[string]bool),
+		changed: make(map[string]bool),
+	}
+
+	var players []models.Player
+	cursor, err := MongoInstance.Players.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query players: %v", err)
+	}
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, fmt.Errorf("failed to decode players: %v", err)
+	}
+	for i := range players {
+		registry.add(&players[i])
+	}
+
+	var aliases []models.PlayerAlias
+	cursor, err = MongoInstance.Aliases.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query player aliases: %v", err)
+	}
+	if err := cursor.All(ctx, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to decode player aliases: %v", err)
+	}
+	for _, alias := range aliases {
+		registry.aliases[alias.Source+"|"+alias.NameKey] = alias.PlayerID
+	}
+	return registry, nil
+}
+
+// Match links a row to a canonical player and returns its ID. External IDs win first, then
+// aliases; otherwise players sharing the normalized name are narrowed by position, team and
+// year. A name no player has yet creates a new player. When candidates remain ambiguous no ID
+// is returned and the candidates are listed so the row can be resolved with an alias.
+func (r *PlayerRegistry) Match(row PlayerRow) (string, []models.Player) {
+	if player := r.known(row); player != nil {
+		return r.link(player, row), nil
+	}
+
+	key := utils.NameKey(row.Name)
+	player, ambiguous := narrow(row, r.named(key, true))
+	switch {
+	case player != nil:
+		return r.link(player, row), nil
+	case len(ambiguous) == 0:
+		return r.link(r.create(row, key), row), nil
+	}
+
+	candidates := make([]models.Player, 0, len(ambiguous))
+	for _, p := range ambiguous {
+		candidates = append(candidates, *p)
+	}
+	return "", candidates
+}
+
+// Resolve looks up the player a stored row refers to without changing the registry,
+// returning "" when there is no single match
+func (r *PlayerRegistry) Resolve(row PlayerRow) string {
+	id, _ := r.Lookup(row)
+	return id
+}
+
+// Lookup is Resolve for rows that shouldn't add players, such as ADP: it also returns the
+// candidates when same-named players can't be told apart
+func (r *PlayerRegistry) Lookup(row PlayerRow) (string, []models.Player) {
+	if player := r.known(row); player != nil {
+		return player.ID, nil
+	}
+	player, ambiguous := narrow(row, r.named(utils.NameKey(row.Name), false))
+	if player != nil {
+		return player.ID, nil
+	}
+	candidates := make([]models.Player, 0, len(ambiguous))
+	for _, p := range ambiguous {
+		candidates = append(candidates, *p)
+	}
+	return "", candidates
+}
+
+// confirm finds the existing player a row certainly refers to, for rows that may only add IDs:
+// the player with one of its external IDs, or else the one same-named player whose team or
+// role agrees with the row and whose IDs don't contradict it. Otherwise it returns the
+// same-named players that couldn't be confirmed.
+func (r *PlayerRegistry) confirm(row PlayerRow) (*models.Player, []*models.Player) {
+	for _, key := range externalKeys(row.IDs) {
+		if id, ok := r.byID[key]; ok {
+			return r.players[id], nil
+		}
+	}
+
+	named := r.named(utils.NameKey(row.Name), false)
+	backed := filterPlayers(named, func(p *models.Player) bool {
+		if conflictingIDs(p.PlayerIDs, row.IDs) {
+			return false
+		}
+		return sameTeam(p.Team, row.Team) || (row.Position != "" && slices.Contains(p.Roles, row.Position))
+	})
+	if len(backed) > 1 {
+		// Prefer the players both team and role back
+		both := filterPlayers(backed, func(p *models.Player) bool {
+			return sameTeam(p.Team, row.Team) && slices.Contains(p.Roles, row.Position)
+		})
+		if len(both) > 0 {
+			backed = both
+		}
+	}
+	if len(backed) == 1 {
+		return backed[0], nil
+	}
+	return nil, named
+}
+
+// conflictingIDs reports whether two sets of external IDs give different IDs on one site
+func conflictingIDs(a, b models.PlayerIDs) bool {
+	differ := func(x, y string) bool { return x != "" && y != "" && x != y }
+	return differ(a.MLBAM, b.MLBAM) || differ(a.FanGraphs, b.FanGraphs) || differ(a.FantasyPros, b.FantasyPros)
+}
+
+// AddAlias links a source's spelling of a name to a player; it is saved with the registry
+func (r *PlayerRegistry) AddAlias(alias models.PlayerAlias) {
+	alias.NameKey = utils.NameKey(alias.Name)
+	if r.aliases[alias.Source+"|"+alias.NameKey] == alias.PlayerID {
+		return
+	}
+	r.aliases[alias.Source+"|"+alias.NameKey] = alias.PlayerID
+	r.newAliases = append(r.newAliases, alias)
+}
+
+// Save writes the players and aliases created or updated since loading back to the registry
+func (r *PlayerRegistry) Save(ctx context.Context) error {
+	var writes []mongo.WriteModel
+	for id := range r.changed {
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetReplacement(r.players[id]).
+			SetUpsert(true))
+	}
+	if len(writes) > 0 {
+		if _, err := MongoInstance.Players.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return fmt.Errorf("failed to save players: %v", err)
+		}
+	}
+
+	for _, alias := range r.newAliases {
+		if err := saveAlias(ctx, alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// known finds the player a row names by external ID or alias
+func (r *PlayerRegistry) known(row PlayerRow) *models.Player {
+	for _, key := range externalKeys(row.IDs) {
+		if id, ok := r.byID[key]; ok {
+			return r.players[id]
+		}
+	}
+
+	key := utils.NameKey(row.Name)
+	for _, aliasKey := range []string{row.Source + "|" + key, "|" + key} {
+		if id, ok := r.aliases[aliasKey]; ok && r.players[id] != nil {
+			return r.players[id]
+		}
+	}
+	return nil
+}
+
+// named lists the players with a name key, optionally skipping those already claimed
+func (r *PlayerRegistry) named(key string, unclaimedOnly bool) []*models.Player {
+	var players []*models.Player
+	for _, id := range r.byName[key] {
+		if unclaimedOnly && r.claimed[id] {
+			continue
+		}
+		players = append(players, r.players[id])
+	}
+	return players
+}
+
+// narrow picks the player a row refers to among same-named players. It returns the
+// remaining candidates when they can't be told apart, and nothing when none fit.
+func narrow(row PlayerRow, pool []*models.Player) (*models.Player, []*models.Player) {
+	sameRole := filterPlayers(pool, func(p *models.Player) bool {
+		return row.Position == "" || len(p.Roles) == 0 || slices.Contains(p.Roles, row.Position)
+	})
+	if len(sameRole) == 0 {
+		// A same-named player in the other role on the same team is a two-way player
+		teammates := filterPlayers(pool, func(p *models.Player) bool { return sameTeam(p.Team, row.Team) })
+		if len(teammates) == 1 {
+			return teammates[0], nil
+		}
+		return nil, nil
+	}
+
+	candidates := sameRole
+	if len(candidates) > 1 {
+		if narrowed := filterPlayers(candidates, func(p *models.Player) bool { return sameTeam(p.Team, row.Team) }); len(narrowed) > 0 {
+			candidates = narrowed
+		}
+	}
+	if len(candidates) > 1 {
+		if narrowed := filterPlayers(candidates, func(p *models.Player) bool { return slices.Contains(p.Years, row.Year) }); len(narrowed) > 0 {
+			candidates = narrowed
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	return nil, candidates
+}
+
+func (r *PlayerRegistry) add(player *models.Player) {
+	r.players[player.ID] = player
+	r.byName[player.NameKey] = append(r.byName[player.NameKey], player.ID)
+	for _, key := range externalKeys(player.PlayerIDs) {
+		r.byID[key] = player.ID
+	}
+}
+
+func (r *PlayerRegistry) create(row PlayerRow, key string) *models.Player {
+	player := &models.Player{
+		ID:      primitive.NewObjectID().Hex(),
+		Name:    row.Name,
+		NameKey: key,
+		Team:    row.Team,
+	}
+	r.add(player)
+	r.changed[player.ID] = true
+	return player
+}
+
+// link claims player for this upload and records the row's role, year, team, IDs and
+// eligibility on it
+func (r *PlayerRegistry) link(player *models.Player, row PlayerRow) string {
+	r.claimed[player.ID] = true
+	if row.Position != "" && !slices.Contains(player.Roles, row.Position) {
+		player.Roles = append(player.Roles, row.Position)
+		r.changed[player.ID] = true
+	}
+	if row.Year != "" && !slices.Contains(player.Years, row.Year) {
+		// Keep the team from the latest year seen
+		if row.Team != "" && (len(player.Years) == 0 || row.Year >= latest(player.Years)) {
+			player.Team = row.Team
+		}
+		player.Years = append(player.Years, row.Year)
+		r.changed[player.ID] = true
+	}
+	if r.attachIDs(player, row.IDs) {
+		r.changed[player.ID] = true
+	}
+	if recordPositions(player, row) {
+		r.changed[player.ID] = true
+	}
+	return player.ID
+}
+
+// recordPositions keeps the eligibility of the latest year a source listed positions for,
+// combining what every source lists that year. Rows without positions (FanGraphs) leave
+// the player's eligibility alone, so those sources inherit it through the match.
+func recordPositions(player *models.Player, row PlayerRow) bool {
+	if len(row.Positions) == 0 || row.Year < player.PositionsYear {
+		return false
+	}
+	positions := row.Positions
+	if row.Year == player.PositionsYear {
+		positions = models.SortPositions(slices.Concat(player.Positions, row.Positions))
+	}
+	if slices.Equal(positions, player.Positions) && row.Year == player.PositionsYear {
+		return false
+	}
+	player.Positions = positions
+	player.PositionsYear = row.Year
+	return true
+}
+
+// Player returns a registry player by ID
+func (r *PlayerRegistry) Player(id string) (models.Player, bool) {
+	player, ok := r.players[id]
+	if !ok {
+		return models.Player{}, false
+	}
+	return *player, true
+}
+
+// attachIDs fills in the external IDs a player doesn't have yet; IDs already set are kept
+func (r *PlayerRegistry) attachIDs(player *models.Player, ids models.PlayerIDs) bool {
+	attached := false
+	set := func(current *string, id string) {
+		if *current == "" && id != "" {
+			*current = id
+			attached = true
+		}
+	}
+	set(&player.MLBAM, ids.MLBAM)
+	set(&player.FanGraphs, ids.FanGraphs)
+	set(&player.FantasyPros, ids.FantasyPros)
+	if attached {
+		for _, key := range externalKeys(player.PlayerIDs) {
+			r.byID[key] = player.ID
+		}
+	}
+	return attached
+}
+
+// externalKeys lists the byID keys for a set of external IDs
+func externalKeys(ids models.PlayerIDs) []string {
+	var keys []string
+	if ids.MLBAM != "" {
+		keys = append(keys, "mlbam:"+ids.MLBAM)
+	}
+	if ids.FanGraphs != "" {
+		keys = append(keys, "fangraphs:"+ids.FanGraphs)
+	}
+	if ids.FantasyPros != "" {
+		keys = append(keys, "fantasypros:"+ids.FantasyPros)
+	}
+	return keys
+}
+
+// FindPlayers lists registry players whose normalized name contains name, optionally on one
+// team (either filter may be empty)
+func FindPlayers(name string, team string) ([]models.Player, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if key := utils.NameKey(name); key != "" {
+		filter["name_key"] = bson.M{"$regex": regexp.QuoteMeta(key)}
+	}
+	if team != "" {
+		// Registry teams saved before canonicalization may still use a source's code
+		codes := []string{models.CanonicalTeam(team)}
+		if canonical, ok := models.LookupTeam(team); ok {
+			codes = append(codes, canonical.Aliases...)
+		}
+		filter["team"] = bson.M{"$in": codes}
+	}
+	cursor, err := MongoInstance.Players.Find(ctx, filter, options.Find().SetSort(bson.M{"name_key": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query players: %v", err)
+	}
+	players := []models.Player{}
+	if err := cursor.All(ctx, &players); err != nil {
+		return nil, fmt.Errorf("failed to decode players: %v", err)
+	}
+	return players, nil
+}
+
+// SavePlayerAlias links a source's spelling of a name to a registry player, replacing any
+// earlier alias for the same name and source
+func SavePlayerAlias(alias models.PlayerAlias) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := MongoInstance.Players.FindOne(ctx, bson.M{"_id": alias.PlayerID}).Err(); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, alias.PlayerID)
+		}
+		return fmt.Errorf("failed to look up player: %v", err)
+	}
+
+	alias.NameKey = utils.NameKey(alias.Name)
+	return saveAlias(ctx, alias)
+}
+
+func saveAlias(ctx context.Context, alias models.PlayerAlias) error {
+	filter := bson.M{"name_key": alias.NameKey, "source": alias.Source}
+	if alias.Source == "" {
+		filter["source"] = bson.M{"$exists": false}
+	}
+	_, err := MongoInstance.Aliases.ReplaceOne(ctx, filter, alias, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save alias: %v", err)
+	}
+	return nil
+}
+
+func filterPlayers(players []*models.Player, keep func(*models.Player) bool) []*models.Player {
+	var kept []*models.Player
+	for _, p := range players {
+		if keep(p) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// sameTeam compares team codes across sources ("KCR" and "KC" are the same club)
+func sameTeam(a, b string) bool {
+	return a != "" && models.CanonicalTeam(a) == models.CanonicalTeam(b)
+}
+
+func latest(years []string) string {
+	max := ""
+	for _, y := range years {
+		if y > max {
+			max = y
+		}
+	}
+	return max
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/db"
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultADPLimit is how many players each list of the ADP report has when none is requested
+const defaultADPLimit = 25
+
+// adpTable is the uploaded ADP players go at, keyed by registry player ID, or "name:" and the
+// name key for rows the registry couldn't match. Players several formats list get their mean.
+type adpTable map[string]float64
+
+// loadADPTable reads the ADP the settings pick, by default for the request's year, returning
+// the year it's from
+func loadADPTable(ctx context.Context, request models.ProjectionRequest, adp *models.ADPSettings) (adpTable, string, error) {
+	var settings models.ADPSettings
+	if adp != nil {
+		settings = *adp
+	}
+	year := settings.Year
+	if year == "" {
+		year = request.Year
+	}
+	entries, year, err := db.LoadADP(ctx, settings.Source, year)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		key := entry.PlayerID
+		if key == "" {
+			key = "name:" + utils.NameKey(entry.Name)
+		}
+		sums[key] += entry.ADP
+		counts[key]++
+	}
+	table := make(adpTable, len(sums))
+	for key, sum := range sums {
+		table[key] = sum / float64(counts[key])
+	}
+	return table, year, nil
+}
+
+// of finds a player's ADP: by registry ID, or by name for players the registry couldn't match
+func (t adpTable) of(player *poolPlayer) (float64, bool) {
+	key := player.ID
+	if key == "" {
+		key = "name:" + utils.NameKey(player.Name)
+	}
+	adp, ok := t[key]
+	return adp, ok
+}
+
+// compareADP ranks the rows that have an ADP by value and by ADP. Rows are first sorted (see
+// sortRows), so ties always rank the same way. A two-way player is ranked once, as their more
+// valuable half. The comparisons line up with the rows, nil for those not ranked.
+func compareADP(rows []exportRow, table adpTable) []*models.ADPComparison {
+	sortRows(rows)
+
+	var ranked []int
+	var values, adps []float64
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		adp, ok := table.of(row.player)
+		if !ok || (row.player.ID != "" && seen[row.player.ID]) {
+			continue
+		}
+		seen[row.player.ID] = true
+		ranked = append(ranked, i)
+		values = append(values, row.value)
+		adps = append(adps, adp)
+	}
+
+	comparisons := make([]*models.ADPComparison, len(rows))
+	for k, ranks := range baseball.RankADP(values, adps) {
+		row := rows[ranked[k]]
+		comparisons[ranked[k]] = &models.ADPComparison{
+			PlayerID:   row.player.ID,
+			PlayerName: row.player.Name,
+			Position:   row.player.Position,
+			Team:       row.player.Team,
+			Value:      row.value,
+			ValueRank:  ranks.Value,
+			ADP:        adps[k],
+			ADPRank:    ranks.ADP,
+			Difference: ranks.ADP - ranks.Value,
+		}
+	}
+	return comparisons
+}
+
+// adpCells are a row's ADP and how many places its ADP rank trails its value rank, blank for
+// rows that have no ADP
+func adpCells(comparison *models.ADPComparison) []string {
+	if comparison == nil {
+		return []string{"", ""}
+	}
+	return []string{fmt.Sprintf("%.1f", comparison.ADP), fmt.Sprintf("%d", comparison.Difference)}
+}
+
+// PlayerADP compares every stored player's value with their uploaded ADP. Players are valued
+// like the export, then ranked by value and by ADP among the players that have both; the
+// report lists the biggest value picks (ADP rank well after value rank) and overdrafts.
+func PlayerADP(c *gin.Context) {
+	var request models.ADPRequest
+	if _, ok := bindProjectionRequest(c, &request); !ok {
+		return
+	}
+	valuation, ok := newValuation(c, request.ProjectionRequest)
+	if !ok {
+		return
+	}
+	limit := defaultADPLimit
+	if request.ADP != nil {
+		if request.ADP.Limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ADP settings: limit must not be negative"})
+			return
+		}
+		if request.ADP.Limit > 0 {
+			limit = request.ADP.Limit
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	table, year, err := loadADPTable(ctx, request.ProjectionRequest, request.ADP)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load ADP: " + err.Error()})
+		return
+	}
+	if len(table) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No ADP has been uploaded for these settings"})
+		return
+	}
+	pool, err := loadPlayerPool(ctx, request.ProjectionRequest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return
+	}
+	_, rows := valuation.rows(pool, false)
+
+	report := models.ADPReport{Year: year, ValuePicks: []models.ADPComparison{}, Overdrafts: []models.ADPComparison{}}
+	if request.ADP != nil {
+		report.Source = request.ADP.Source
+	}
+	for _, comparison := range compareADP(rows, table) {
+		switch {
+		case comparison == nil:
+			continue
+		case comparison.Difference > 0:
+			report.ValuePicks = append(report.ValuePicks, *comparison)
+		case comparison.Difference < 0:
+			report.Overdrafts = append(report.Overdrafts, *comparison)
+		}
+		report.Players++
+	}
+	for _, list := range []*[]models.ADPComparison{&report.ValuePicks, &report.Overdrafts} {
+		sort.SliceStable(*list, func(i, j int) bool {
+			a, b := (*list)[i], (*list)[j]
+			if abs(a.Difference) != abs(b.Difference) {
+				return abs(a.Difference) > abs(b.Difference)
+			}
+			return a.ValueRank < b.ValueRank
+		})
+		if len(*list) > limit {
+			*list = (*list)[:limit]
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"adp": report})
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
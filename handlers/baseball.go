@@ -2,14 +2,13 @@ package handlers
 
 import (
 	"bytes"
-	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
-	"time"
 
 	"super-fantasy-api/data/baseball"
 	"super-fantasy-api/db"
@@ -17,7 +16,6 @@ import (
 	"super-fantasy-api/utils"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 // CalculateBaseballProjections handles fetching projections from MongoDB
@@ -46,155 +44,50 @@ func CalculateBaseballProjections(c *gin.Context) {
 		return
 	}
 
-	// Get league settings from form field
-	settingsStr := c.Request.FormValue("settings") // Use FormValue instead of PostForm
-	if settingsStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing or empty settings field"})
+	// Get league settings from the form, or the saved league they name
+	var request models.ProjectionRequest
+	if _, ok := bindProjectionRequest(c, &request); !ok {
 		return
 	}
 
-	// Debug: Log the raw settings string to verify
-	c.Request.ParseMultipartForm(10 << 20) // Parse form if not already done (10MB max)
-	c.Writer.WriteString("Debug: settingsStr = " + settingsStr + "\n")
+	if len(records) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV has no header row"})
+		return
+	}
+	header, rows := records[0], records[1:]
 
-	var request models.ProjectionRequest
-	if err := json.Unmarshal([]byte(settingsStr), &request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league settings format: " + err.Error()})
+	source, position, err := resolveSource(request.Source, "", request.Position, header)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Process CSV records into projections (assuming Batter format for now)
+	scorer, err := baseball.NewScorer(request.Settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scoring rules: " + err.Error()})
+		return
+	}
+
+	// Map the header onto the source's columns, then score each row
+	columns, err := utils.MapColumns(header, source.NewRow(position))
+	if err != nil {
+		respondCSVError(c, err)
+		return
+	}
+	meta := baseball.RowMeta{Year: request.Year, Source: source.Name(), Position: position}
 	var projections []models.PlayerProjection
-	for i, record := range records {
-		if i == 0 {
-			continue // Skip header
+	for _, record := range rows {
+		row := source.NewRow(position)
+		baseball.DecodeRow(columns, record, row, meta)
+		line := row.Line()
+		if !matchesTeams(request.Teams, line.Team) {
+			continue
 		}
-
-		// Dispatch to appropriate save function based on source and position
-		switch request.Source {
-		case "fangraphs":
-			switch request.Position {
-			case "batter":
-				player := baseball.FangraphsBatter{
-					Rank:           utils.ParseInt(record[0]),
-					Name:           record[1],
-					Team:           record[2],
-					Games:          utils.ParseFloat(record[3]),
-					AtBats:         utils.ParseFloat(record[4]),
-					PlateApps:      utils.ParseFloat(record[5]),
-					Hits:           utils.ParseFloat(record[6]),
-					Singles:        utils.ParseFloat(record[7]),
-					Doubles:        utils.ParseFloat(record[8]),
-					Triples:        utils.ParseFloat(record[9]),
-					HomeRuns:       utils.ParseFloat(record[10]),
-					Runs:           utils.ParseFloat(record[11]),
-					RBI:            utils.ParseFloat(record[12]),
-					Walks:          utils.ParseFloat(record[13]),
-					IntWalks:       utils.ParseFloat(record[14]),
-					Strikeouts:     utils.ParseFloat(record[15]),
-					HitByPitch:     utils.ParseFloat(record[16]),
-					SacFlies:       utils.ParseFloat(record[17]),
-					SacHits:        utils.ParseFloat(record[18]),
-					StolenBases:    utils.ParseFloat(record[19]),
-					CaughtStealing: utils.ParseFloat(record[20]),
-					AVG:            utils.ParseFloat(record[21]),
-					Year:           request.Year,
-					Position:       request.Position,
-				}
-
-				playerProjection := baseball.CalculateBatterPoints(player, request.Settings)
-				projections = append(projections, playerProjection)
-			case "pitcher":
-				player := baseball.FangraphsPitcher{
-					Rank:              utils.ParseInt(record[0]),
-					Name:              record[1],
-					Team:              record[2],
-					Wins:              utils.ParseFloat(record[3]),
-					Losses:            utils.ParseFloat(record[4]),
-					ERA:               utils.ParseFloat(record[5]),
-					Games:             utils.ParseFloat(record[6]),
-					GamesStarted:      utils.ParseFloat(record[7]),
-					Saves:             utils.ParseFloat(record[8]),
-					Holds:             utils.ParseFloat(record[9]),
-					BlownSaves:        utils.ParseFloat(record[10]),
-					InningsPitched:    utils.ParseFloat(record[11]),
-					TotalBattersFaced: utils.ParseFloat(record[12]),
-					HitsAllowed:       utils.ParseFloat(record[13]),
-					RunsAllowed:       utils.ParseFloat(record[14]),
-					EarnedRuns:        utils.ParseFloat(record[15]),
-					HomeRunsAllowed:   utils.ParseFloat(record[16]),
-					Walks:             utils.ParseFloat(record[17]),
-					IntWalks:          utils.ParseFloat(record[18]),
-					HitByPitch:        utils.ParseFloat(record[19]),
-					Strikeouts:        utils.ParseFloat(record[20]),
-					Year:              request.Year,
-					Position:          request.Position,
-				}
-
-				playerProjection := baseball.CalculatePitcherPoints(player, request.Settings)
-				projections = append(projections, playerProjection)
-			default:
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position: must be 'batter' or 'pitcher'"})
-				return
-			}
-		case "fantasypros":
-			switch request.Position {
-			case "batter":
-				player := baseball.FantasyProsBatter{
-					Name:        record[0],
-					Team:        record[1],
-					Positions:   record[2],
-					AtBats:      utils.ParseFloat(record[3]),
-					Runs:        utils.ParseFloat(record[4]),
-					HomeRuns:    utils.ParseFloat(record[5]),
-					RBI:         utils.ParseFloat(record[6]),
-					StolenBases: utils.ParseFloat(record[7]),
-					AVG:         utils.ParseFloat(record[8]),
-					OBP:         utils.ParseFloat(record[9]),
-					Hits:        utils.ParseFloat(record[10]),
-					Doubles:     utils.ParseFloat(record[11]),
-					Triples:     utils.ParseFloat(record[12]),
-					Walks:       utils.ParseFloat(record[13]),
-					Strikeouts:  utils.ParseFloat(record[14]),
-					SLG:         utils.ParseFloat(record[15]),
-					OPS:         utils.ParseFloat(record[16]),
-					Year:        request.Year,
-					Position:    request.Position,
-				}
-				playerProjection := baseball.CalculateFantasyProsBatterPoints(player, request.Settings)
-				projections = append(projections, playerProjection)
-			case "pitcher":
-				player := baseball.FantasyProsPitcher{
-					Name:            record[0],
-					Team:            record[1],
-					Positions:       record[2],
-					InningsPitched:  utils.ParseFloat(record[3]),
-					Strikeouts:      utils.ParseFloat(record[4]),
-					Wins:            utils.ParseFloat(record[5]),
-					Saves:           utils.ParseFloat(record[6]),
-					ERA:             utils.ParseFloat(record[7]),
-					WHIP:            utils.ParseFloat(record[8]),
-					EarnedRuns:      utils.ParseFloat(record[9]),
-					HitsAllowed:     utils.ParseFloat(record[10]),
-					Walks:           utils.ParseFloat(record[11]),
-					HomeRunsAllowed: utils.ParseFloat(record[12]),
-					Games:           utils.ParseFloat(record[13]),
-					GamesStarted:    utils.ParseFloat(record[14]),
-					Losses:          utils.ParseFloat(record[15]),
-					CompleteGames:   utils.ParseFloat(record[16]),
-					Year:            request.Year,
-					Position:        request.Position,
-				}
-				playerProjection := baseball.CalculateFantasyProsPitcherPoints(player, request.Settings)
-				projections = append(projections, playerProjection)
-			default:
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position: must be 'batter' or 'pitcher'"})
-				return
-			}
-		default:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source: must be 'fangraphs' or 'fantasypros'"})
-			return
+		projection := scorer.Score(line)
+		if !request.Breakdown {
+			projection.Breakdown = nil
 		}
+		projections = append(projections, projection)
 	}
 
 	// Return projections as JSON
@@ -235,362 +128,156 @@ func UploadCSV(c *gin.Context) {
 		return
 	}
 
-	// Dispatch to appropriate save function based on source and position
-	switch request.Source {
-	case "fangraphs":
-		switch request.Position {
-		case "batter":
-			err = db.SaveFanGraphsBatterCSV(buf.String(), request.Year, request.Suffix, request.Position)
-		case "pitcher":
-			err = db.SaveFanGraphsPitcherCSV(buf.String(), request.Year, request.Suffix, request.Position)
-		default:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position: must be 'batter' or 'pitcher'"})
+	header, err := csv.NewReader(strings.NewReader(buf.String())).Read()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read CSV header: " + err.Error()})
+		return
+	}
+	isADP, err := adpUpload(request, header)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var report models.UploadReport
+	if isADP {
+		var format baseball.ADPFormat
+		if format, err = resolveADPFormat(request.Source, header); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-	case "fantasypros":
-		switch request.Position {
-		case "batter":
-			err = db.SaveFantasyProsBatterCSV(buf.String(), request.Year, request.Position)
-		case "pitcher":
-			err = db.SaveFantasyProsPitcherCSV(buf.String(), request.Year, request.Position)
-		default:
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position: must be 'batter' or 'pitcher'"})
+		report, err = db.SaveADPCSV(buf.String(), format, request)
+	} else {
+		var source baseball.ProjectionSource
+		if source, request.Position, err = resolveSource(request.Source, request.Suffix, request.Position, header); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source: must be 'fangraphs' or 'fantasypros'"})
-		return
+		report, err = db.SaveProjectionCSV(buf.String(), source, request)
 	}
-
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CSV: " + err.Error()})
+		respondCSVError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "CSV uploaded and saved successfully"})
-}
-
-func ExportPlayerPointsCSV(c *gin.Context) {
-	// Get league settings from form field
-	settingsStr := c.Request.FormValue("settings")
-	if settingsStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing or empty settings field"})
+	if request.DryRun {
+		c.JSON(http.StatusOK, gin.H{"message": "Dry run: nothing was saved", "report": report})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"message": "CSV uploaded and saved successfully", "report": report})
+}
 
-	var request models.ProjectionRequest
-	if err := json.Unmarshal([]byte(settingsStr), &request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league settings format: " + err.Error()})
-		return
+// resolveSource picks the registered source and position a request refers to. An empty source
+// or position is detected from the CSV header; the suffix selects a system within a site's
+// shared format ("fangraphs" + "atc").
+func resolveSource(name, suffix, position string, header []string) (baseball.ProjectionSource, string, error) {
+	if name == "" {
+		detected, detectedPosition, ok := baseball.DetectSource(header)
+		if !ok {
+			return nil, "", fmt.Errorf("Could not detect the source from the CSV header; set source to one of: %s", strings.Join(baseball.SourceNames(), ", "))
+		}
+		name = detected.Name()
+		if position == "" {
+			position = detectedPosition
+		}
 	}
 
-	// Query all documents from the Baseball collection
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	cursor, err := db.MongoInstance.Collection.Find(ctx, bson.M{})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query MongoDB: " + err.Error()})
-		return
+	source, ok := baseball.LookupSource(baseball.SourceName(name, suffix))
+	if !ok {
+		return nil, "", fmt.Errorf("Invalid source %q: must be one of: %s", baseball.SourceName(name, suffix), strings.Join(baseball.SourceNames(), ", "))
 	}
-	defer cursor.Close(ctx)
-
-	// Map to store player points: key is "Name:Position", value is a map of source to points
-	playerPoints := make(map[string]map[string]float64)
-
-	// Process each document
-	for cursor.Next(ctx) {
-		var doc bson.M
-		if err := cursor.Decode(&doc); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode document: " + err.Error()})
-			return
+	if position == "" {
+		if detected, ok := source.Detect(header); ok {
+			position = detected
 		}
+	}
+	if !baseball.Supports(source, position) {
+		return nil, "", fmt.Errorf("Invalid position %q: %s projects %s", position, source.Name(), strings.Join(source.Positions(), " and "))
+	}
+	return source, position, nil
+}
 
-		rawName, _ := doc["name"].(string)
-		name := utils.NormalizeName(rawName)
-		source, _ := doc["source"].(string)
-		var position string
-		var points float64
-
-		// Determine position and calculate points based on source
-		switch source {
-		case "fantasypros":
-			if _, isBatter := doc["at_bats"]; isBatter {
-				position = "Batter"
-				player := baseball.FantasyProsBatter{
-					Name:        name,
-					Team:        utils.GetString(doc, "team"),
-					Positions:   utils.GetString(doc, "positions"),
-					AtBats:      utils.GetFloat64(doc, "at_bats"),
-					Runs:        utils.GetFloat64(doc, "runs"),
-					HomeRuns:    utils.GetFloat64(doc, "home_runs"),
-					RBI:         utils.GetFloat64(doc, "rbi"),
-					StolenBases: utils.GetFloat64(doc, "stolen_bases"),
-					AVG:         utils.GetFloat64(doc, "avg"),
-					OBP:         utils.GetFloat64(doc, "obp"),
-					Hits:        utils.GetFloat64(doc, "hits"),
-					Doubles:     utils.GetFloat64(doc, "doubles"),
-					Triples:     utils.GetFloat64(doc, "triples"),
-					Walks:       utils.GetFloat64(doc, "walks"),
-					Strikeouts:  utils.GetFloat64(doc, "strikeouts"),
-					SLG:         utils.GetFloat64(doc, "slg"),
-					OPS:         utils.GetFloat64(doc, "ops"),
-					Year:        utils.GetString(doc, "year"),
-					Source:      source,
-					Position:    position,
-				}
-				proj := baseball.CalculateFantasyProsBatterPoints(player, request.Settings)
-				points = proj.TotalPoints
-			} else if _, isPitcher := doc["innings_pitched"]; isPitcher {
-				position = "Pitcher"
-				player := baseball.FantasyProsPitcher{
-					Name:            name,
-					Team:            utils.GetString(doc, "team"),
-					Positions:       utils.GetString(doc, "positions"),
-					InningsPitched:  utils.GetFloat64(doc, "innings_pitched"),
-					Strikeouts:      utils.GetFloat64(doc, "strikeouts"),
-					Wins:            utils.GetFloat64(doc, "wins"),
-					Saves:           utils.GetFloat64(doc, "saves"),
-					ERA:             utils.GetFloat64(doc, "era"),
-					WHIP:            utils.GetFloat64(doc, "whip"),
-					EarnedRuns:      utils.GetFloat64(doc, "earned_runs"),
-					HitsAllowed:     utils.GetFloat64(doc, "hits_allowed"),
-					Walks:           utils.GetFloat64(doc, "walks"),
-					HomeRunsAllowed: utils.GetFloat64(doc, "home_runs_allowed"),
-					Games:           utils.GetFloat64(doc, "games"),
-					GamesStarted:    utils.GetFloat64(doc, "games_started"),
-					Losses:          utils.GetFloat64(doc, "losses"),
-					CompleteGames:   utils.GetFloat64(doc, "complete_games"),
-					Year:            utils.GetString(doc, "year"),
-					Source:          source,
-					Position:        position,
-				}
-				proj := baseball.CalculateFantasyProsPitcherPoints(player, request.Settings)
-				points = proj.TotalPoints
-			}
-		case "fangraphs_atc":
-			position = "Pitcher"
-			player := baseball.FangraphsPitcher{
-				Rank:              utils.GetInt(doc, "rank"),
-				Name:              name,
-				Team:              utils.GetString(doc, "team"),
-				Wins:              utils.GetFloat64(doc, "wins"),
-				Losses:            utils.GetFloat64(doc, "losses"),
-				ERA:               utils.GetFloat64(doc, "era"),
-				Games:             utils.GetFloat64(doc, "games"),
-				GamesStarted:      utils.GetFloat64(doc, "games_started"),
-				Saves:             utils.GetFloat64(doc, "saves"),
-				Holds:             utils.GetFloat64(doc, "holds"),
-				BlownSaves:        utils.GetFloat64(doc, "blown_saves"),
-				InningsPitched:    utils.GetFloat64(doc, "innings_pitched"),
-				TotalBattersFaced: utils.GetFloat64(doc, "total_batters_faced"),
-				HitsAllowed:       utils.GetFloat64(doc, "hits_allowed"),
-				RunsAllowed:       utils.GetFloat64(doc, "runs_allowed"),
-				EarnedRuns:        utils.GetFloat64(doc, "earned_runs"),
-				HomeRunsAllowed:   utils.GetFloat64(doc, "home_runs_allowed"),
-				Walks:             utils.GetFloat64(doc, "walks"),
-				IntWalks:          utils.GetFloat64(doc, "int_walks"),
-				HitByPitch:        utils.GetFloat64(doc, "hit_by_pitch"),
-				Strikeouts:        utils.GetFloat64(doc, "strikeouts"),
-				Year:              utils.GetString(doc, "year"),
-				Source:            source,
-				Position:          position,
-			}
-			proj := baseball.CalculatePitcherPoints(player, request.Settings)
-			points = proj.TotalPoints
-		case "fangraphs_batx":
-			position = "Batter"
-			player := baseball.FangraphsBatter{
-				Rank:           utils.GetInt(doc, "rank"),
-				Name:           name,
-				Team:           utils.GetString(doc, "team"),
-				Games:          utils.GetFloat64(doc, "games"),
-				AtBats:         utils.GetFloat64(doc, "at_bats"),
-				PlateApps:      utils.GetFloat64(doc, "plate_apps"),
-				Hits:           utils.GetFloat64(doc, "hits"),
-				Singles:        utils.GetFloat64(doc, "singles"),
-				Doubles:        utils.GetFloat64(doc, "doubles"),
-				Triples:        utils.GetFloat64(doc, "triples"),
-				HomeRuns:       utils.GetFloat64(doc, "home_runs"),
-				Runs:           utils.GetFloat64(doc, "runs"),
-				RBI:            utils.GetFloat64(doc, "rbi"),
-				Walks:          utils.GetFloat64(doc, "walks"),
-				IntWalks:       utils.GetFloat64(doc, "int_walks"),
-				Strikeouts:     utils.GetFloat64(doc, "strikeouts"),
-				HitByPitch:     utils.GetFloat64(doc, "hit_by_pitch"),
-				SacFlies:       utils.GetFloat64(doc, "sac_flies"),
-				SacHits:        utils.GetFloat64(doc, "sac_hits"),
-				StolenBases:    utils.GetFloat64(doc, "stolen_bases"),
-				CaughtStealing: utils.GetFloat64(doc, "caught_stealing"),
-				AVG:            utils.GetFloat64(doc, "avg"),
-				Year:           utils.GetString(doc, "year"),
-				Source:         source,
-				Position:       position,
-			}
-			proj := baseball.CalculateBatterPoints(player, request.Settings)
-			points = proj.TotalPoints
-		case "fangraphs_steamer":
-			if _, isBatter := doc["at_bats"]; isBatter {
-				position = "Batter"
-				player := baseball.FangraphsBatter{
-					Rank:           utils.GetInt(doc, "rank"),
-					Name:           name,
-					Team:           utils.GetString(doc, "team"),
-					Games:          utils.GetFloat64(doc, "games"),
-					AtBats:         utils.GetFloat64(doc, "at_bats"),
-					PlateApps:      utils.GetFloat64(doc, "plate_apps"),
-					Hits:           utils.GetFloat64(doc, "hits"),
-					Singles:        utils.GetFloat64(doc, "singles"),
-					Doubles:        utils.GetFloat64(doc, "doubles"),
-					Triples:        utils.GetFloat64(doc, "triples"),
-					HomeRuns:       utils.GetFloat64(doc, "home_runs"),
-					Runs:           utils.GetFloat64(doc, "runs"),
-					RBI:            utils.GetFloat64(doc, "rbi"),
-					Walks:          utils.GetFloat64(doc, "walks"),
-					IntWalks:       utils.GetFloat64(doc, "int_walks"),
-					Strikeouts:     utils.GetFloat64(doc, "strikeouts"),
-					HitByPitch:     utils.GetFloat64(doc, "hit_by_pitch"),
-					SacFlies:       utils.GetFloat64(doc, "sac_flies"),
-					SacHits:        utils.GetFloat64(doc, "sac_hits"),
-					StolenBases:    utils.GetFloat64(doc, "stolen_bases"),
-					CaughtStealing: utils.GetFloat64(doc, "caught_stealing"),
-					AVG:            utils.GetFloat64(doc, "avg"),
-					Year:           utils.GetString(doc, "year"),
-					Source:         source,
-					Position:       position,
-				}
-				proj := baseball.CalculateBatterPoints(player, request.Settings)
-				points = proj.TotalPoints
-			} else if _, isPitcher := doc["innings_pitched"]; isPitcher {
-				position = "Pitcher"
-				player := baseball.FangraphsPitcher{
-					Rank:              utils.GetInt(doc, "rank"),
-					Name:              name,
-					Team:              utils.GetString(doc, "team"),
-					Wins:              utils.GetFloat64(doc, "wins"),
-					Losses:            utils.GetFloat64(doc, "losses"),
-					ERA:               utils.GetFloat64(doc, "era"),
-					Games:             utils.GetFloat64(doc, "games"),
-					GamesStarted:      utils.GetFloat64(doc, "games_started"),
-					Saves:             utils.GetFloat64(doc, "saves"),
-					Holds:             utils.GetFloat64(doc, "holds"),
-					BlownSaves:        utils.GetFloat64(doc, "blown_saves"),
-					InningsPitched:    utils.GetFloat64(doc, "innings_pitched"),
-					TotalBattersFaced: utils.GetFloat64(doc, "total_batters_faced"),
-					HitsAllowed:       utils.GetFloat64(doc, "hits_allowed"),
-					RunsAllowed:       utils.GetFloat64(doc, "runs_allowed"),
-					EarnedRuns:        utils.GetFloat64(doc, "earned_runs"),
-					HomeRunsAllowed:   utils.GetFloat64(doc, "home_runs_allowed"),
-					Walks:             utils.GetFloat64(doc, "walks"),
-					IntWalks:          utils.GetFloat64(doc, "int_walks"),
-					HitByPitch:        utils.GetFloat64(doc, "hit_by_pitch"),
-					Strikeouts:        utils.GetFloat64(doc, "strikeouts"),
-					Year:              utils.GetString(doc, "year"),
-					Source:            source,
-				}
-				proj := baseball.CalculatePitcherPoints(player, request.Settings)
-				points = proj.TotalPoints
-			}
-		default:
-			continue // Skip unknown sources
-		}
+// adpUpload reports whether an upload is an ADP export: one whose type says so, whose source
+// only names an ADP format, or, with neither, whose header only an ADP format matches
+func adpUpload(request models.UploadRequest, header []string) (bool, error) {
+	switch request.Type {
+	case models.UploadADP:
+		return true, nil
+	case models.UploadProjections:
+		return false, nil
+	case "":
+	default:
+		return false, fmt.Errorf("Invalid type %q: must be %q or %q", request.Type, models.UploadProjections, models.UploadADP)
+	}
 
-		// Key for map: "Name:Position"
-		key := fmt.Sprintf("%s:%s", name, position)
-		if _, exists := playerPoints[key]; !exists {
-			playerPoints[key] = make(map[string]float64)
-		}
+	if request.Source != "" {
+		_, projections := baseball.LookupSource(baseball.SourceName(request.Source, request.Suffix))
+		_, adp := baseball.LookupADPFormat(request.Source)
+		return adp && !projections, nil
+	}
+	if _, _, ok := baseball.DetectSource(header); ok {
+		return false, nil
+	}
+	_, ok := baseball.DetectADPFormat(header)
+	return ok, nil
+}
 
-		// Map source to column name
-		var column string
-		switch source {
-		case "fantasypros":
-			column = "FantasyPros"
-		case "fangraphs_atc":
-			column = "FangraphsATC"
-		case "fangraphs_batx":
-			column = "FangraphsBatX"
-		case "fangraphs_steamer":
-			column = "Steamer"
+// resolveADPFormat picks the ADP format a request names, detecting it from the header when
+// the request has no source
+func resolveADPFormat(name string, header []string) (baseball.ADPFormat, error) {
+	if name == "" {
+		format, ok := baseball.DetectADPFormat(header)
+		if !ok {
+			return baseball.ADPFormat{}, fmt.Errorf("Could not detect the ADP format from the CSV header; set source to one of: %s", strings.Join(baseball.ADPFormatNames(), ", "))
 		}
-		playerPoints[key][column] = points
+		return format, nil
 	}
-
-	if err := cursor.Err(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cursor error: " + err.Error()})
-		return
+	format, ok := baseball.LookupADPFormat(name)
+	if !ok {
+		return baseball.ADPFormat{}, fmt.Errorf("Invalid ADP source %q: must be one of: %s", name, strings.Join(baseball.ADPFormatNames(), ", "))
 	}
+	return format, nil
+}
 
-	// Generate CSV
-	var csvBuf bytes.Buffer
-	writer := csv.NewWriter(&csvBuf)
-
-	// Write headers (added "Aggregate")
-	headers := []string{"Player", "Position", "FantasyPros", "FangraphsATC", "FangraphsBatX", "Steamer", "Aggregate"}
-	if err := writer.Write(headers); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write CSV headers: " + err.Error()})
+// respondCSVError reports a CSV processing failure, answering 400 with the missing columns when
+// the CSV header could not be mapped onto the source's fields
+func respondCSVError(c *gin.Context, err error) {
+	var missing *utils.MissingColumnsError
+	if errors.As(err, &missing) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CSV header: " + err.Error(), "missing_columns": missing.Missing})
 		return
 	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process CSV: " + err.Error()})
+}
 
-	// Write rows with aggregate
-	for key, pointsMap := range playerPoints {
-		parts := strings.Split(key, ":")
-		name := parts[0]
-		position := parts[1]
-
-		// Calculate aggregate
-		var sum float64
-		var count int
-		if val := pointsMap["FantasyPros"]; val != 0 {
-			sum += val
-			count++
-		}
-		if position == "Pitcher" && pointsMap["FangraphsATC"] != 0 {
-			sum += pointsMap["FangraphsATC"]
-			count++
-		}
-		if position == "Batter" && pointsMap["FangraphsBatX"] != 0 {
-			sum += pointsMap["FangraphsBatX"]
-			count++
-		}
-		if val := pointsMap["Steamer"]; val != 0 {
-			sum += val
-			count++
-		}
-		aggregate := 0.0
-		if count > 0 {
-			aggregate = sum / float64(count)
-		}
-
-		row := []string{
-			name,
-			position,
-			fmt.Sprintf("%.1f", pointsMap["FantasyPros"]),
-			fmt.Sprintf("%.1f", pointsMap["FangraphsATC"]),
-			fmt.Sprintf("%.1f", pointsMap["FangraphsBatX"]),
-			fmt.Sprintf("%.1f", pointsMap["Steamer"]),
-			fmt.Sprintf("%.1f", aggregate),
-		}
-		// Replace "0.0" with "" for missing values (except Aggregate)
-		for i := 2; i < len(row)-1; i++ { // Skip Aggregate column
-			if row[i] == "0.0" {
-				row[i] = ""
-			}
-		}
-		if err := writer.Write(row); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write CSV row: " + err.Error()})
-			return
+// matchesTeams reports whether team passes a request's team filter; an empty filter matches
+// every team and codes from any source are accepted ("KCR" selects "KC")
+func matchesTeams(teams []string, team string) bool {
+	if len(teams) == 0 {
+		return true
+	}
+	for _, code := range teams {
+		if models.CanonicalTeam(code) == team {
+			return true
 		}
 	}
+	return false
+}
 
-	writer.Flush()
-	if err := writer.Error(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to flush CSV writer: " + err.Error()})
-		return
+// ListSources returns the registered projection sources with the positions each one projects,
+// and the ADP formats
+func ListSources(c *gin.Context) {
+	sources := []gin.H{}
+	for _, source := range baseball.Sources() {
+		sources = append(sources, gin.H{"name": source.Name(), "label": source.Label(), "positions": source.Positions()})
 	}
+	formats := []gin.H{}
+	for _, format := range baseball.ADPFormats() {
+		formats = append(formats, gin.H{"name": format.Name, "label": format.Label})
+	}
+	c.JSON(http.StatusOK, gin.H{"sources": sources, "adp_sources": formats})
+}
 
-	// Set response headers and send CSV
-	c.Header("Content-Disposition", "attachment; filename=player_points.csv")
-	c.Header("Content-Type", "text/csv")
-	c.Data(http.StatusOK, "text/csv", csvBuf.Bytes())
+// ListTeams returns the canonical MLB team table
+func ListTeams(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"teams": models.Teams})
 }
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlayerDisagreement reports how much the sources disagree on every stored player's points:
+// the standard deviation, lowest, highest and range of their projections, and the sources far
+// from the rest. Players the sources disagree on most come first. The points are the same
+// per-source points the export shows.
+func PlayerDisagreement(c *gin.Context) {
+	var request models.DisagreementRequest
+	if _, ok := bindProjectionRequest(c, &request); !ok {
+		return
+	}
+	if request.Categories != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Disagreement compares the sources' points, which categories leagues don't have"})
+		return
+	}
+	valuation, ok := newValuation(c, request.ProjectionRequest)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := loadPlayerPool(ctx, request.ProjectionRequest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return
+	}
+	_, rows := valuation.rows(pool, false)
+
+	spreads := make([]models.PlayerSpread, 0, len(rows))
+	for _, row := range rows {
+		if len(row.scored) == 0 {
+			continue
+		}
+		spread := baseball.MeasureSpread(row.scored, row.points, request.OutlierThreshold)
+		points := make(map[string]float64)
+		for i, name := range row.scored {
+			points[sourceLabel(name)] = row.points[i]
+		}
+		outliers := make([]string, len(spread.Outliers))
+		for i, name := range spread.Outliers {
+			outliers[i] = sourceLabel(name)
+		}
+		spreads = append(spreads, models.PlayerSpread{
+			PlayerName: row.player.Name,
+			Position:   row.player.Position,
+			Team:       row.player.Team,
+			Points:     points,
+			Aggregate:  row.value,
+			Sources:    spread.Sources,
+			StdDev:     spread.StdDev,
+			Min:        spread.Min,
+			Max:        spread.Max,
+			Range:      spread.Range,
+			Outliers:   outliers,
+		})
+	}
+	sort.SliceStable(spreads, func(i, j int) bool {
+		if spreads[i].StdDev != spreads[j].StdDev {
+			return spreads[i].StdDev > spreads[j].StdDev
+		}
+		return spreads[i].PlayerName < spreads[j].PlayerName
+	})
+
+	c.JSON(http.StatusOK, gin.H{"players": spreads})
+}
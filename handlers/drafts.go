@@ -0,0 +1,607 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/db"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// bestAvailable is how many available players a draft board lists
+const bestAvailable = 50
+
+// errInvalidPick is returned for picks and undos the draft doesn't allow
+var errInvalidPick = errors.New("invalid pick")
+
+// errRoomClosed is returned by a room that closed after it was opened
+var errRoomClosed = errors.New("draft room closed")
+
+// draftRoom is a draft open on the server: its picks, its league's players valued the way the
+// export values them, and the clients following it over WebSocket. Rooms open on first use and
+// close once their draft is complete or deleted or their last client leaves, so player values
+// are the ones stored when the room opened.
+type draftRoom struct {
+	mu      sync.Mutex
+	draft   models.Draft
+	players []models.DraftPlayer // best first
+	byID    map[string]int       // a player ID's best entry in players
+	clients map[*draftClient]bool
+	closed  bool // the room has left rooms; whoever still holds it opens the draft again
+	// saving is set while a pick or undo is written, without the lock held; the next one
+	// waits on saved
+	saving bool
+	saved  sync.Cond
+}
+
+// draftClient is a WebSocket connection to a draft room. Only its write loop writes to the
+// connection; messages are queued on send.
+type draftClient struct {
+	conn *websocket.Conn
+	send chan []byte
+}
+
+// draftMessage is a pick or undo sent by a draft room client
+type draftMessage struct {
+	Action   string  `json:"action"` // "pick" or "undo"
+	Team     string  `json:"team"`
+	PlayerID string  `json:"player_id"`
+	Price    float64 `json:"price"`
+}
+
+var (
+	roomsMu sync.Mutex
+	rooms   = make(map[string]*draftRoom)
+)
+
+// allowedOrigins are the browser origins other than the API's own that may join draft rooms
+var allowedOrigins []string
+
+// upgrader lets a browser join a draft room only from the API's own host or an allowed origin
+var upgrader = websocket.Upgrader{CheckOrigin: checkOrigin}
+
+// AllowOrigins sets the browser origins ("https://app.example.com") other than the API's own
+// that may join draft rooms over WebSocket
+func AllowOrigins(origins []string) {
+	allowedOrigins = origins
+}
+
+// checkOrigin accepts WebSocket requests without an Origin header, which browsers always send,
+// and those from the request's own host or an allowed origin
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(parsed.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range allowedOrigins {
+		if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateDraft starts a draft for a saved league. The body names the league and optionally the
+// draft type ("snake", the default, or "auction") and the teams, in first-round order; roster
+// spots and auction terms are taken from the league.
+func CreateDraft(c *gin.Context) {
+	var draft models.Draft
+	if err := c.ShouldBindJSON(&draft); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft format: " + err.Error()})
+		return
+	}
+	if draft.LeagueID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft: league_id is required"})
+		return
+	}
+	league, err := db.GetLeague(draft.LeagueID)
+	if err != nil {
+		respondLeagueError(c, err)
+		return
+	}
+	if err := draftTerms(&draft, league); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid draft: " + err.Error()})
+		return
+	}
+	draft, err = db.CreateDraft(draft)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save draft: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"draft": draft})
+}
+
+// draftTerms checks a new draft and fixes its terms from the league: teams named "Team 1" and
+// on unless given, and the league's roster spots, budget and minimum bid
+func draftTerms(draft *models.Draft, league models.League) error {
+	switch draft.Type {
+	case "":
+		draft.Type = models.DraftSnake
+	case models.DraftSnake, models.DraftAuction:
+	default:
+		return fmt.Errorf("type must be '%s' or '%s'", models.DraftSnake, models.DraftAuction)
+	}
+	if strings.TrimSpace(draft.Name) == "" {
+		draft.Name = league.Name + " draft"
+	}
+
+	teams := league.Teams
+	if len(draft.Teams) > 0 {
+		if teams > 0 && len(draft.Teams) != teams {
+			return fmt.Errorf("the league has %d teams, not %d", teams, len(draft.Teams))
+		}
+		teams = len(draft.Teams)
+		for i, team := range draft.Teams {
+			if strings.TrimSpace(team) == "" || slices.Contains(draft.Teams[:i], team) {
+				return fmt.Errorf("team names must be unique and not empty")
+			}
+		}
+	}
+	auction, err := baseball.NewAuction(models.AuctionSettings{Teams: teams, Budget: league.Budget, Roster: league.Roster})
+	if err != nil {
+		return err
+	}
+	terms := auction.Settings()
+	for i := len(draft.Teams); i < terms.Teams; i++ {
+		draft.Teams = append(draft.Teams, fmt.Sprintf("Team %d", i+1))
+	}
+	draft.Spots = (auction.Roster().Starters() + auction.Roster().Bench()) / terms.Teams
+	draft.Budget, draft.MinBid = 0, 0
+	if draft.Type == models.DraftAuction {
+		draft.Budget, draft.MinBid = terms.Budget, terms.MinBid
+	}
+	draft.Picks = nil
+	return nil
+}
+
+// GetDraftBoard returns a draft's board: its picks, the best available players and every
+// team's roster
+func GetDraftBoard(c *gin.Context) {
+	inRoom(c, (*draftRoom).view)
+}
+
+// MakeDraftPick records a pick (team, player_id and, for an auction, price) and pushes the new
+// board to the draft room
+func MakeDraftPick(c *gin.Context) {
+	var message draftMessage
+	if err := c.ShouldBindJSON(&message); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pick format: " + err.Error()})
+		return
+	}
+	inRoom(c, func(room *draftRoom) (models.DraftBoard, error) {
+		return room.pick(models.DraftPick{Team: message.Team, PlayerID: message.PlayerID, Price: message.Price})
+	})
+}
+
+// UndoDraftPick takes back a draft's last pick and pushes the new board to the draft room
+func UndoDraftPick(c *gin.Context) {
+	inRoom(c, (*draftRoom).undo)
+}
+
+// DeleteDraft removes a draft, closing its room and disconnecting its clients
+func DeleteDraft(c *gin.Context) {
+	id := c.Param("id")
+	if err := db.DeleteDraft(id); err != nil {
+		respondDraftError(c, err)
+		return
+	}
+	roomsMu.Lock()
+	room, ok := rooms[id]
+	roomsMu.Unlock()
+	if ok {
+		room.mu.Lock()
+		room.close()
+		room.mu.Unlock()
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Draft deleted successfully"})
+}
+
+// inRoom runs action in the draft's room and answers with the board it returns. A room that
+// closed in the meantime is opened again.
+func inRoom(c *gin.Context, action func(*draftRoom) (models.DraftBoard, error)) {
+	for {
+		room, ok := openRoom(c, c.Param("id"))
+		if !ok {
+			return
+		}
+		board, err := action(room)
+		if errors.Is(err, errRoomClosed) {
+			continue
+		}
+		if err != nil {
+			respondPickError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"board": board})
+		return
+	}
+}
+
+// DraftSocket joins a draft room over WebSocket. The client is sent the board on joining and
+// after every pick or undo, whoever makes it, and can itself send {"action": "pick", "team",
+// "player_id", "price"} or {"action": "undo"}. A message the draft refuses is answered with
+// {"error": ...} to that client alone.
+func DraftSocket(c *gin.Context) {
+	// The client joins before the upgrade, while failing to open the room can still be answered
+	client := &draftClient{send: make(chan []byte, 16)}
+	var room *draftRoom
+	for joined := false; !joined; {
+		var ok bool
+		if room, ok = openRoom(c, c.Param("id")); !ok {
+			return
+		}
+		joined = room.join(client)
+	}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		room.leave(client)
+		return // the upgrader has answered the request
+	}
+	client.conn = conn
+	go client.write()
+
+	defer room.leave(client)
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return // the client went away
+		}
+		var message draftMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			room.reply(client, gin.H{"error": "Invalid message: " + err.Error()})
+			continue
+		}
+		switch message.Action {
+		case "pick":
+			_, err = room.pick(models.DraftPick{Team: message.Team, PlayerID: message.PlayerID, Price: message.Price})
+		case "undo":
+			_, err = room.undo()
+		default:
+			err = fmt.Errorf("%w: action must be 'pick' or 'undo'", errInvalidPick)
+		}
+		if err != nil {
+			room.reply(client, gin.H{"error": err.Error()})
+		}
+	}
+}
+
+// respondPickError answers 400 for picks the draft refuses, 404 for a draft deleted from under
+// its room and 500 otherwise
+func respondPickError(c *gin.Context, err error) {
+	if errors.Is(err, errInvalidPick) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	respondDraftError(c, err)
+}
+
+// respondDraftError answers 404 for unknown drafts and 500 otherwise
+func respondDraftError(c *gin.Context, err error) {
+	if errors.Is(err, db.ErrDraftNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// openRoom returns a draft's room, opening it if needed. Rooms are loaded outside roomsMu, so
+// opening one doesn't hold up every other draft; when two requests open the same room at once,
+// the first one stored is kept.
+func openRoom(c *gin.Context, id string) (*draftRoom, bool) {
+	roomsMu.Lock()
+	room, ok := rooms[id]
+	roomsMu.Unlock()
+	if ok {
+		return room, true
+	}
+
+	if room, ok = loadRoom(c, id); !ok {
+		return nil, false
+	}
+	roomsMu.Lock()
+	defer roomsMu.Unlock()
+	if open, ok := rooms[id]; ok {
+		return open, true
+	}
+	rooms[id] = room
+	return room, true
+}
+
+// loadRoom loads a draft's room: the draft with its league, and the stored projections valued
+// with the league's scoring (and priced, for an auction)
+func loadRoom(c *gin.Context, id string) (*draftRoom, bool) {
+	draft, err := db.GetDraft(id)
+	if err != nil {
+		respondDraftError(c, err)
+		return nil, false
+	}
+	league, err := db.GetLeague(draft.LeagueID)
+	if err != nil {
+		respondLeagueError(c, err)
+		return nil, false
+	}
+	var request models.ProjectionRequest
+	applyLeague(&request, league)
+	valuation, ok := newValuation(c, request)
+	if !ok {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := loadPlayerPool(ctx, request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return nil, false
+	}
+	_, rows := valuation.rows(pool, false)
+	var prices []baseball.AuctionValue
+	if draft.Type == models.DraftAuction {
+		auction, err := baseball.NewAuction(models.AuctionSettings{
+			Teams:  len(draft.Teams),
+			Budget: draft.Budget,
+			MinBid: draft.MinBid,
+			Roster: league.Roster,
+		})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid auction settings: " + err.Error()})
+			return nil, false
+		}
+		prices = priceRows(auction, rows, request.Eligibility)
+	}
+
+	room := &draftRoom{draft: draft, byID: make(map[string]int), clients: make(map[*draftClient]bool)}
+	room.saved.L = &room.mu
+	for i, row := range rows {
+		player := models.DraftPlayer{
+			PlayerID:   row.player.DraftID(),
+			PlayerName: row.player.Name,
+			Position:   row.player.Position,
+			Team:       row.player.Team,
+			Eligible:   row.player.Eligible(request.Eligibility),
+			Value:      row.value,
+		}
+		if prices != nil {
+			player.Dollars = prices[i].Dollars
+		}
+		room.players = append(room.players, player)
+	}
+	sort.SliceStable(room.players, func(i, j int) bool {
+		if room.players[i].Value != room.players[j].Value {
+			return room.players[i].Value > room.players[j].Value
+		}
+		return room.players[i].PlayerName < room.players[j].PlayerName
+	})
+	for i := len(room.players) - 1; i >= 0; i-- {
+		room.byID[room.players[i].PlayerID] = i
+	}
+	return room, true
+}
+
+// board is the room's current board. The caller holds the room's lock.
+func (r *draftRoom) board() models.DraftBoard {
+	taken := make(map[string]bool, len(r.draft.Picks))
+	for _, pick := range r.draft.Picks {
+		taken[pick.PlayerID] = true
+	}
+	available := []models.DraftPlayer{}
+	for _, player := range r.players {
+		if len(available) == bestAvailable {
+			break
+		}
+		if !taken[player.PlayerID] {
+			available = append(available, player)
+		}
+	}
+	return models.DraftBoard{
+		Draft:      r.draft,
+		OnTheClock: baseball.OnTheClock(r.draft),
+		Available:  available,
+		Rosters:    baseball.TeamRosters(r.draft),
+	}
+}
+
+// view is the room's board. A complete draft's room closes once it's been viewed.
+func (r *draftRoom) view() (models.DraftBoard, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return models.DraftBoard{}, errRoomClosed
+	}
+	board := r.board()
+	if baseball.DraftComplete(r.draft) {
+		r.close()
+	}
+	return board, nil
+}
+
+// pick records a pick, saves the draft and pushes the new board to every client
+func (r *draftRoom) pick(pick models.DraftPick) (models.DraftBoard, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.await(); err != nil {
+		return models.DraftBoard{}, err
+	}
+
+	index, ok := r.byID[pick.PlayerID]
+	if !ok {
+		return models.DraftBoard{}, fmt.Errorf("%w: unknown player %q", errInvalidPick, pick.PlayerID)
+	}
+	pick.PlayerName = r.players[index].PlayerName
+	pick.Position = r.players[index].Position
+	pick.Number = len(r.draft.Picks) + 1
+	pick.PickedAt = time.Now().UTC()
+	if err := baseball.CheckPick(r.draft, pick); err != nil {
+		return models.DraftBoard{}, fmt.Errorf("%w: %v", errInvalidPick, err)
+	}
+	return r.save(append(slices.Clone(r.draft.Picks), pick))
+}
+
+// undo takes back the last pick, saves the draft and pushes the new board to every client
+func (r *draftRoom) undo() (models.DraftBoard, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.await(); err != nil {
+		return models.DraftBoard{}, err
+	}
+
+	if len(r.draft.Picks) == 0 {
+		return models.DraftBoard{}, fmt.Errorf("%w: no picks to undo", errInvalidPick)
+	}
+	return r.save(slices.Clone(r.draft.Picks[:len(r.draft.Picks)-1]))
+}
+
+// await waits for the pick or undo being saved, if any, reporting errRoomClosed if the room
+// closed. The caller holds the room's lock.
+func (r *draftRoom) await() error {
+	for r.saving {
+		r.saved.Wait()
+	}
+	if r.closed {
+		return errRoomClosed
+	}
+	return nil
+}
+
+// save stores the draft's new picks and broadcasts the board, closing the room once the draft
+// is complete. The write is made without the lock, so clients can join and leave meanwhile. A
+// draft saved elsewhere since the room opened closes the room without saving, as does the
+// room's draft changing during the write; a write that succeeds after the room closed is only
+// answered. The caller holds the room's lock, having awaited any other save.
+func (r *draftRoom) save(picks []models.DraftPick) (models.DraftBoard, error) {
+	id, since := r.draft.ID, r.draft.UpdatedAt
+	r.saving = true
+	r.mu.Unlock()
+	updated, err := db.SaveDraftPicks(id, since, picks)
+	r.mu.Lock()
+	r.saving = false
+	r.saved.Broadcast()
+
+	stale := r.closed || !r.draft.UpdatedAt.Equal(since)
+	if errors.Is(err, db.ErrDraftChanged) || err != nil && stale {
+		r.close()
+		return models.DraftBoard{}, errRoomClosed
+	}
+	if errors.Is(err, db.ErrDraftNotFound) {
+		r.close()
+	}
+	if err != nil {
+		return models.DraftBoard{}, err
+	}
+	r.draft.Picks = picks
+	r.draft.UpdatedAt = updated
+	if stale {
+		r.close()
+		return r.board(), nil
+	}
+
+	board := r.board()
+	message, _ := json.Marshal(gin.H{"board": board})
+	for client := range r.clients {
+		select {
+		case client.send <- message:
+		default:
+			// A client too far behind is dropped; closing its queue ends its connection
+			delete(r.clients, client)
+			close(client.send)
+		}
+	}
+	if baseball.DraftComplete(r.draft) {
+		r.close()
+	}
+	return board, nil
+}
+
+// close takes the room out of rooms and disconnects its clients once they've been sent what's
+// queued for them. The caller holds the room's lock.
+func (r *draftRoom) close() {
+	r.closed = true
+	roomsMu.Lock()
+	if rooms[r.draft.ID] == r {
+		delete(rooms, r.draft.ID)
+	}
+	roomsMu.Unlock()
+	for client := range r.clients {
+		delete(r.clients, client)
+		close(client.send)
+	}
+}
+
+// join adds a client to the room and queues the current board for it, reporting false if the
+// room has closed. A client joining a complete draft is sent the board and disconnected.
+func (r *draftRoom) join(client *draftClient) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return false
+	}
+	r.clients[client] = true
+	message, _ := json.Marshal(gin.H{"board": r.board()})
+	client.send <- message
+	if baseball.DraftComplete(r.draft) {
+		r.close()
+	}
+	return true
+}
+
+// leave removes a client from the room, closing the room when it was the last; the next
+// request opens it again from the stored draft
+func (r *draftRoom) leave(client *draftClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.clients[client] {
+		delete(r.clients, client)
+		close(client.send)
+	}
+	// A client dropped for falling behind has already been removed, but still leaves last
+	if len(r.clients) == 0 && !r.closed {
+		r.close()
+	}
+}
+
+// reply queues a message for one client, if it's still in the room
+func (r *draftRoom) reply(client *draftClient, payload gin.H) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.clients[client] {
+		return
+	}
+	message, _ := json.Marshal(payload)
+	select {
+	case client.send <- message:
+	default:
+	}
+}
+
+// write sends the client's queued messages until its queue is closed, then closes the
+// connection. A failed write closes the connection early, which ends the client's read loop.
+func (client *draftClient) write() {
+	defer client.conn.Close()
+	for message := range client.send {
+		client.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := client.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			client.conn.Close()
+		}
+	}
+}
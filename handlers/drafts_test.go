@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"super-fantasy-api/models"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	AllowOrigins([]string{"https://app.example.com/"})
+	defer AllowOrigins(nil)
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"", true},
+		{"http://api.example.com:8080", true},
+		{"https://app.example.com", true},
+		{"https://APP.example.com", true},
+		{"https://evil.example.com", false},
+		{"http://app.example.com", false},
+		{"://bad", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "http://api.example.com:8080/api/v1/drafts/1/ws", nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if got := checkOrigin(r); got != tt.want {
+			t.Errorf("checkOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestDraftRoomClosesWhenLastClientLeaves(t *testing.T) {
+	draft := models.Draft{ID: "room-test", Type: models.DraftSnake, Teams: []string{"A", "B"}, Spots: 2}
+	room := &draftRoom{draft: draft, byID: make(map[string]int), clients: make(map[*draftClient]bool)}
+	room.saved.L = &room.mu
+	roomsMu.Lock()
+	rooms[draft.ID] = room
+	roomsMu.Unlock()
+
+	first, second := &draftClient{send: make(chan []byte, 16)}, &draftClient{send: make(chan []byte, 16)}
+	if !room.join(first) || !room.join(second) {
+		t.Fatal("couldn't join an open room")
+	}
+	room.leave(first)
+	roomsMu.Lock()
+	_, open := rooms[draft.ID]
+	roomsMu.Unlock()
+	if !open || room.closed {
+		t.Fatal("the room closed with a client still in it")
+	}
+
+	room.leave(second)
+	roomsMu.Lock()
+	_, open = rooms[draft.ID]
+	roomsMu.Unlock()
+	if open || !room.closed {
+		t.Error("the room stayed open after its last client left")
+	}
+	if room.join(first) {
+		t.Error("a client joined a closed room")
+	}
+}
+
+func TestDraftRoomWaitsForSave(t *testing.T) {
+	draft := models.Draft{ID: "save-test", Type: models.DraftSnake, Teams: []string{"A", "B"}, Spots: 2}
+	room := &draftRoom{draft: draft, byID: make(map[string]int), clients: make(map[*draftClient]bool)}
+	room.saved.L = &room.mu
+	room.saving = true
+
+	// A pick waits for the save in flight, but joining doesn't
+	picked := make(chan error)
+	go func() {
+		_, err := room.undo()
+		picked <- err
+	}()
+	if !room.join(&draftClient{send: make(chan []byte, 16)}) {
+		t.Fatal("couldn't join while a pick was being saved")
+	}
+	select {
+	case err := <-picked:
+		t.Fatalf("undo didn't wait for the save: %v", err)
+	default:
+	}
+
+	// The save finishing as the room closes sends the waiting undo to a fresh room
+	room.mu.Lock()
+	room.saving = false
+	room.close()
+	room.saved.Broadcast()
+	room.mu.Unlock()
+	if err := <-picked; !errors.Is(err, errRoomClosed) {
+		t.Errorf("undo = %v, want errRoomClosed", err)
+	}
+}
@@ -0,0 +1,497 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/db"
+	"super-fantasy-api/models"
+	"super-fantasy-api/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// poolPlayer is one player joined across sources: the name, position and team of an export
+// row, and each source's stat line
+type poolPlayer struct {
+	ID       string // registry player ID, empty for rows the registry couldn't match
+	Name     string
+	Position string // "Batter" or "Pitcher"
+	Team     string
+	Lines    map[string]baseball.Line // by source name
+	// Listed are the positions the player is eligible at: the registry player's, or for rows
+	// the registry couldn't match, what their sources list
+	Listed []string
+}
+
+// playerPool is every stored projection passing a request's team filter, joined per player
+type playerPool struct {
+	// Players are keyed "PlayerID:Position", or "Name:Position" for rows the registry
+	// couldn't match
+	Players map[string]*poolPlayer
+	Sources []baseball.ProjectionSource // registered sources with stored projections, in registration order
+}
+
+// loadPlayerPool reads the stored projections for the request's year, by default the latest
+// uploaded, and joins the sources on the canonical player. Rows uploaded before they were
+// linked to the registry are resolved by ID, then name.
+func loadPlayerPool(ctx context.Context, request models.ProjectionRequest) (*playerPool, error) {
+	registry, err := db.LoadPlayerRegistry(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load players: %v", err)
+	}
+
+	filter, _, err := db.VisibleYear(ctx, db.MongoInstance.Collection, bson.M{}, request.Year)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := db.MongoInstance.Collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query MongoDB: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	pool := &playerPool{Players: make(map[string]*poolPlayer)}
+	exported := make(map[string]bool)
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to decode document: %v", err)
+		}
+
+		rawName, _ := doc["name"].(string)
+		name := utils.NormalizeName(rawName)
+		source, _ := doc["source"].(string)
+		team := models.CanonicalTeam(utils.GetString(doc, "team"))
+		if !matchesTeams(request.Teams, team) {
+			continue
+		}
+		line, ok, err := storedLine(cursor, doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode document: %v", err)
+		}
+		if !ok {
+			continue // Skip unknown sources
+		}
+		position := "Batter"
+		if line.Position == "pitcher" {
+			position = "Pitcher"
+		}
+
+		// Join sources on the canonical player, falling back to the normalized name
+		key := fmt.Sprintf("%s:%s", name, position)
+		playerID := utils.GetString(doc, "player_id")
+		if playerID == "" {
+			playerID = registry.Resolve(db.PlayerRow{
+				Name:     rawName,
+				Team:     team,
+				Position: strings.ToLower(position),
+				Year:     utils.GetString(doc, "year"),
+				Source:   source,
+				IDs:      line.IDs,
+			})
+		}
+		if playerID != "" {
+			key = fmt.Sprintf("%s:%s", playerID, position)
+		}
+		player, exists := pool.Players[key]
+		if !exists {
+			player = &poolPlayer{ID: playerID, Name: name, Position: position, Team: team, Lines: make(map[string]baseball.Line)}
+			if registered, ok := registry.Player(playerID); ok {
+				player.Listed = registered.Positions
+			}
+			pool.Players[key] = player
+		}
+		player.Lines[source] = line
+		if playerID == "" {
+			player.Listed = models.SortPositions(slices.Concat(player.Listed, models.ParsePositions(line.Positions)))
+		}
+		exported[source] = true
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("cursor error: %v", err)
+	}
+
+	for _, source := range baseball.Sources() {
+		if exported[source.Name()] {
+			pool.Sources = append(pool.Sources, source)
+		}
+	}
+	return pool, nil
+}
+
+// SourceLines lists the player's lines in source registration order
+func (p *poolPlayer) SourceLines() []baseball.Line {
+	var lines []baseball.Line
+	for _, source := range baseball.Sources() {
+		if line, ok := p.Lines[source.Name()]; ok {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// DraftID is how drafts refer to the player: their registry ID, which a two-way player's
+// batting and pitching entries share, or "Name:Position", their pool key, for players the
+// registry couldn't match
+func (p *poolPlayer) DraftID() string {
+	if p.ID != "" {
+		return p.ID
+	}
+	return p.Name + ":" + p.Position
+}
+
+// Consensus combines the player's lines with an equally weighted mean of each stat
+func (p *poolPlayer) Consensus() baseball.Line {
+	return baseball.Consensus(p.SourceLines())
+}
+
+// Eligible lists the positions the player can fill (see baseball.Eligible)
+func (p *poolPlayer) Eligible(settings models.EligibilitySettings) []string {
+	return baseball.Eligible(p.Consensus(), p.Listed, settings)
+}
+
+// storedLine decodes a saved projection into its source's row type and converts it to a stat
+// line. ok is false for documents whose source isn't registered or doesn't project the position.
+func storedLine(cursor *mongo.Cursor, doc bson.M) (line baseball.Line, ok bool, err error) {
+	source, known := baseball.LookupSource(utils.GetString(doc, "source"))
+	if !known {
+		return line, false, nil
+	}
+	position := storedPosition(doc)
+	if !baseball.Supports(source, position) {
+		return line, false, nil
+	}
+
+	row := source.NewRow(position)
+	if err := cursor.Decode(row); err != nil {
+		return line, false, err
+	}
+	if pitcher, isFangraphs := row.(*baseball.FangraphsPitcher); isFangraphs {
+		pitcher.Outs = storedOuts(doc)
+	}
+	return row.Line(), true, nil
+}
+
+// storedPosition reads a document's position, telling batters and pitchers apart by their
+// fields for documents that don't have one
+func storedPosition(doc bson.M) string {
+	if position := utils.GetString(doc, "position"); position != "" {
+		return position
+	}
+	if _, isBatter := doc["at_bats"]; isBatter {
+		return "batter"
+	}
+	if _, isPitcher := doc["innings_pitched"]; isPitcher {
+		return "pitcher"
+	}
+	return ""
+}
+
+// storedOuts reads a FanGraphs pitcher's outs, falling back to innings_pitched for documents
+// saved before outs were stored, when IP was kept in baseball notation
+func storedOuts(doc bson.M) utils.Outs {
+	if _, ok := doc["outs"]; ok {
+		return utils.Outs(utils.GetFloat64(doc, "outs"))
+	}
+	return utils.OutsFromNotation(utils.GetFloat64(doc, "innings_pitched"))
+}
+
+// exportRow is one player's row of an export: the value rows are ranked by, plus the cells
+// after Player, Position and Team
+type exportRow struct {
+	player  *poolPlayer
+	value   float64
+	sources []string // the sources the value combines
+	cells   []string
+	// scored and points are each source's points for the player, for points leagues
+	scored []string
+	points []float64
+}
+
+// ExportPlayerPointsCSV exports every stored player's projected value: each source's points
+// and their aggregate for points leagues, or category values for categories leagues. With
+// auction terms it adds each player's auction price, and with ADP settings their uploaded ADP.
+func ExportPlayerPointsCSV(c *gin.Context) {
+	// Get league settings from the form, or the saved league they name
+	var request models.ExportRequest
+	league, ok := bindProjectionRequest(c, &request)
+	if !ok {
+		return
+	}
+	valuation, ok := newValuation(c, request.ProjectionRequest)
+	if !ok {
+		return
+	}
+	if request.Spread && valuation.valuer != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Spread compares the sources' points, which categories leagues don't have"})
+		return
+	}
+	var auction *baseball.Auction
+	if request.Auction != nil {
+		if auction, ok = newAuction(c, request.Auction, league); !ok {
+			return
+		}
+	}
+	var tiering *baseball.Tiering
+	if request.Tiers != nil {
+		if tiering, ok = newTiering(c, request.Tiers); !ok {
+			return
+		}
+	}
+
+	// Query all documents from the Baseball collection
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := loadPlayerPool(ctx, request.ProjectionRequest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return
+	}
+
+	headers, rows := valuation.rows(pool, request.Breakdown)
+	filename := "player_points.csv"
+	if valuation.valuer != nil {
+		filename = "player_values.csv"
+	}
+	headers = append(headers, "Sources")
+	for i, row := range rows {
+		rows[i].cells = append(rows[i].cells, sourceLabels(row.sources))
+	}
+	if request.Spread {
+		headers = append(headers, "StdDev", "Min", "Max", "Range", "Outliers")
+		for i, row := range rows {
+			spread := baseball.MeasureSpread(row.scored, row.points, request.OutlierThreshold)
+			if spread.Sources == 0 {
+				rows[i].cells = append(rows[i].cells, "", "", "", "", "")
+				continue
+			}
+			rows[i].cells = append(rows[i].cells,
+				fmt.Sprintf("%.1f", spread.StdDev),
+				fmt.Sprintf("%.1f", spread.Min),
+				fmt.Sprintf("%.1f", spread.Max),
+				fmt.Sprintf("%.1f", spread.Range),
+				sourceLabels(spread.Outliers),
+			)
+		}
+	}
+	if auction != nil {
+		headers = append(headers, "Dollars")
+		for i, price := range priceRows(auction, rows, request.Eligibility) {
+			rows[i].cells = append(rows[i].cells, fmt.Sprintf("%.0f", price.Dollars))
+		}
+	}
+	if tiering != nil {
+		headers = append(headers, "Tier")
+		tiers := tierRows(tiering, rows, request.Eligibility)
+		for i, cell := range tierCells(rows, tiers, request.Eligibility) {
+			rows[i].cells = append(rows[i].cells, cell)
+		}
+	}
+	if request.ADP != nil {
+		table, _, err := loadADPTable(ctx, request.ProjectionRequest, request.ADP)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load ADP: " + err.Error()})
+			return
+		}
+		headers = append(headers, "ADP", "ADP Diff")
+		for i, comparison := range compareADP(rows, table) {
+			rows[i].cells = append(rows[i].cells, adpCells(comparison)...)
+		}
+	}
+
+	// Order rows by team (when grouping) and value
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if request.GroupBy == "team" && a.player.Team != b.player.Team {
+			return a.player.Team < b.player.Team
+		}
+		if a.value != b.value {
+			return a.value > b.value
+		}
+		return a.player.Name < b.player.Name
+	})
+
+	records := [][]string{append([]string{"Player", "Position", "Team"}, headers...)}
+	for _, row := range rows {
+		records = append(records, append([]string{row.player.Name, row.player.Position, row.player.Team}, row.cells...))
+	}
+	sendCSV(c, filename, records)
+}
+
+// pointsRows scores every source's line for each player, with one column per source that has
+// stored projections, the aggregate and, when asked for, each stat's points. The aggregate
+// combines the sources' points, or scores the line combined from their stats.
+func pointsRows(pool *playerPool, scorer *baseball.Scorer, aggregator *baseball.Aggregator, breakdown bool) ([]string, []exportRow) {
+	var headers []string
+	for _, source := range pool.Sources {
+		headers = append(headers, source.Label())
+	}
+	headers = append(headers, "Aggregate")
+	var breakdownStats []models.Stat
+	if breakdown {
+		breakdownStats = scorer.Stats()
+		for _, stat := range breakdownStats {
+			headers = append(headers, string(stat)+" Points")
+		}
+	}
+
+	var rows []exportRow
+	for _, player := range pool.Players {
+		row := exportRow{player: player}
+		var scored []string
+		var scores []models.PlayerProjection
+		for _, source := range pool.Sources {
+			// Leave the cell empty when the source has no projection for the player
+			line, ok := player.Lines[source.Name()]
+			if !ok {
+				row.cells = append(row.cells, "")
+				continue
+			}
+			score := scorer.Score(line)
+			if score.TotalPoints == 0 {
+				row.cells = append(row.cells, "")
+				continue
+			}
+			row.cells = append(row.cells, fmt.Sprintf("%.1f", score.TotalPoints))
+			scored = append(scored, source.Name())
+			scores = append(scores, score)
+			row.points = append(row.points, score.TotalPoints)
+		}
+		row.scored = scored
+
+		points := make(map[models.Stat]float64)
+		if aggregator.Level() == models.LevelStats {
+			lines := player.SourceLines()
+			combined := scorer.Score(aggregator.Line(lines))
+			row.value = combined.TotalPoints
+			row.sources = aggregator.Sources(lines)
+			for stat, entry := range combined.Breakdown {
+				points[stat] = entry.Points
+			}
+		} else {
+			// Sources only store the positions they project, so every score present counts.
+			// The breakdown is combined over the same sources; with the mean its stats add up
+			// to Aggregate.
+			row.value, row.sources = aggregator.Combine(scored, row.points)
+			for _, stat := range breakdownStats {
+				var values []float64
+				found := false
+				for _, score := range scores {
+					entry, ok := score.Breakdown[stat]
+					found = found || ok
+					values = append(values, entry.Points)
+				}
+				if found {
+					points[stat], _ = aggregator.Combine(scored, values)
+				}
+			}
+		}
+
+		row.cells = append(row.cells, fmt.Sprintf("%.1f", row.value))
+		for _, stat := range breakdownStats {
+			// A stat that doesn't apply to the row's position (or no source scored) stays empty
+			cell := ""
+			if value, ok := points[stat]; ok {
+				cell = fmt.Sprintf("%.1f", value)
+			}
+			row.cells = append(row.cells, cell)
+		}
+		rows = append(rows, row)
+	}
+	return headers, rows
+}
+
+// categoryRows values each player's combined line in the league's categories, with one column
+// per category and the total. A stat that's a category for both batters and pitchers (SO)
+// gets a column for each, named by position.
+func categoryRows(pool *playerPool, valuer *baseball.CategoryValuer, aggregator *baseball.Aggregator) ([]string, []exportRow) {
+	batting, pitching := valuer.Categories("batter"), valuer.Categories("pitcher")
+	var headers []string
+	for _, stat := range batting {
+		header := string(stat)
+		if slices.Contains(pitching, stat) {
+			header = "Batter " + header
+		}
+		headers = append(headers, header)
+	}
+	for _, stat := range pitching {
+		header := string(stat)
+		if slices.Contains(batting, stat) {
+			header = "Pitcher " + header
+		}
+		headers = append(headers, header)
+	}
+	headers = append(headers, "Value")
+
+	players := make([]*poolPlayer, 0, len(pool.Players))
+	lines := make([]baseball.Line, 0, len(pool.Players))
+	for _, player := range pool.Players {
+		players = append(players, player)
+		lines = append(lines, aggregator.Line(player.SourceLines()))
+	}
+	values := valuer.Value(lines)
+
+	rows := make([]exportRow, len(players))
+	for i, player := range players {
+		row := exportRow{player: player, value: values[i].Total, sources: aggregator.Sources(player.SourceLines())}
+		for j, stat := range slices.Concat(batting, pitching) {
+			// Only the row's own position's categories (and ones its sources project) are filled
+			position := "batter"
+			if j >= len(batting) {
+				position = "pitcher"
+			}
+			cell := ""
+			if value, ok := values[i].Values[stat]; ok && lines[i].Position == position {
+				cell = fmt.Sprintf("%.2f", value)
+			}
+			row.cells = append(row.cells, cell)
+		}
+		row.cells = append(row.cells, fmt.Sprintf("%.2f", row.value))
+		rows[i] = row
+	}
+	return headers, rows
+}
+
+// sourceLabel is a stored source name's label, or the name for sources no longer registered
+func sourceLabel(name string) string {
+	if source, ok := baseball.LookupSource(name); ok {
+		return source.Label()
+	}
+	return name
+}
+
+// sourceLabels lists sources by label, for the export's Sources column
+func sourceLabels(names []string) string {
+	labels := make([]string, len(names))
+	for i, name := range names {
+		labels[i] = sourceLabel(name)
+	}
+	return strings.Join(labels, "; ")
+}
+
+// sendCSV writes records as a CSV attachment
+func sendCSV(c *gin.Context, filename string, records [][]string) {
+	var csvBuf bytes.Buffer
+	writer := csv.NewWriter(&csvBuf)
+	if err := writer.WriteAll(records); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write CSV: " + err.Error()})
+		return
+	}
+
+	// Set response headers and send CSV
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Header("Content-Type", "text/csv")
+	c.Data(http.StatusOK, "text/csv", csvBuf.Bytes())
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/db"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateLeague saves a league profile
+func CreateLeague(c *gin.Context) {
+	league, ok := bindLeague(c)
+	if !ok {
+		return
+	}
+	league, err := db.CreateLeague(league)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save league: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"league": league})
+}
+
+// ListLeagues returns every saved league profile
+func ListLeagues(c *gin.Context) {
+	leagues, err := db.ListLeagues()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query leagues: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"leagues": leagues})
+}
+
+// GetLeague returns one league profile
+func GetLeague(c *gin.Context) {
+	league, err := db.GetLeague(c.Param("id"))
+	if err != nil {
+		respondLeagueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"league": league})
+}
+
+// UpdateLeague replaces a league profile
+func UpdateLeague(c *gin.Context) {
+	league, ok := bindLeague(c)
+	if !ok {
+		return
+	}
+	league, err := db.UpdateLeague(c.Param("id"), league)
+	if err != nil {
+		respondLeagueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"league": league})
+}
+
+// DeleteLeague removes a league profile
+func DeleteLeague(c *gin.Context) {
+	if err := db.DeleteLeague(c.Param("id")); err != nil {
+		respondLeagueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "League deleted successfully"})
+}
+
+// bindLeague reads and checks a league profile from the JSON body, answering 400 when it's invalid
+func bindLeague(c *gin.Context) (models.League, bool) {
+	var league models.League
+	if err := c.ShouldBindJSON(&league); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league format: " + err.Error()})
+		return league, false
+	}
+	if err := validateLeague(league); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league: " + err.Error()})
+		return league, false
+	}
+	return league, true
+}
+
+func validateLeague(league models.League) error {
+	if strings.TrimSpace(league.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if league.Teams < 0 {
+		return fmt.Errorf("teams can't be negative")
+	}
+	if league.Budget < 0 {
+		return fmt.Errorf("budget can't be negative")
+	}
+	for _, slot := range league.Roster {
+		if strings.TrimSpace(slot.Position) == "" || slot.Count <= 0 {
+			return fmt.Errorf("roster slots need a position and a positive count")
+		}
+	}
+	if _, err := baseball.NewScorer(league.Settings); err != nil {
+		return err
+	}
+	if league.Categories != nil {
+		if _, err := baseball.NewCategoryValuer(*league.Categories); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// respondLeagueError answers 404 for unknown leagues and 500 otherwise
+func respondLeagueError(c *gin.Context, err error) {
+	if errors.Is(err, db.ErrLeagueNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// projectionRequest is an endpoint's request: models.ProjectionRequest, or a request that
+// embeds it with the endpoint's own settings
+type projectionRequest interface {
+	Projection() *models.ProjectionRequest
+}
+
+// bindProjectionRequest reads a projection request from the multipart "settings" field. A saved
+// league stands in for inline settings, named by "league_id" in the settings JSON or as its own
+// form field; its scoring (and categories, for a categories league) replaces the request's, and
+// it's returned for the roster and auction terms. With a preset, the resulting settings are
+// merged on top of the preset's.
+func bindProjectionRequest(c *gin.Context, request projectionRequest) (*models.League, bool) {
+	settingsStr := c.Request.FormValue("settings")
+	leagueID := c.Request.FormValue("league_id")
+	if settingsStr == "" && leagueID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing or empty settings field"})
+		return nil, false
+	}
+	if settingsStr != "" {
+		if err := json.Unmarshal([]byte(settingsStr), request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league settings format: " + err.Error()})
+			return nil, false
+		}
+	}
+	shared := request.Projection()
+	if leagueID != "" {
+		shared.LeagueID = leagueID
+	}
+
+	var league *models.League
+	if shared.LeagueID != "" {
+		saved, err := db.GetLeague(shared.LeagueID)
+		if err != nil {
+			respondLeagueError(c, err)
+			return nil, false
+		}
+		league = &saved
+		applyLeague(shared, saved)
+	}
+
+	if shared.Preset != "" {
+		preset, ok := models.LookupPreset(shared.Preset)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown preset %q", shared.Preset)})
+			return nil, false
+		}
+		shared.Settings = preset.Merge(shared.Settings)
+	}
+	return league, true
+}
+
+// applyLeague values a request the way a saved league does: with its scoring, or its categories
+// (played by the league's teams unless they say otherwise) for a categories league
+func applyLeague(request *models.ProjectionRequest, league models.League) {
+	request.LeagueID = league.ID
+	request.Settings = league.Settings
+	if league.Categories != nil {
+		categories := *league.Categories
+		if categories.Teams == 0 {
+			categories.Teams = league.Teams
+		}
+		request.Categories = &categories
+	}
+}
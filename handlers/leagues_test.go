@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"super-fantasy-api/models"
+)
+
+func TestValidateLeague(t *testing.T) {
+	valid := func(change func(*models.League)) models.League {
+		league := models.League{
+			Name:     "Home League",
+			Teams:    12,
+			Budget:   260,
+			Roster:   []models.RosterSlot{{Position: "C", Count: 1}, {Position: "OF", Count: 3}},
+			Settings: models.LeagueSettings{Rules: []models.ScoringRule{{Position: "batter", Stat: "HR", Weight: 4}}},
+		}
+		change(&league)
+		return league
+	}
+
+	tests := []struct {
+		name    string
+		league  models.League
+		wantErr bool
+	}{
+		{name: "valid", league: valid(func(*models.League) {})},
+		{name: "teams and budget left to the defaults", league: valid(func(l *models.League) { l.Teams, l.Budget = 0, 0 })},
+		{name: "no name", league: valid(func(l *models.League) { l.Name = "  " }), wantErr: true},
+		{name: "negative teams", league: valid(func(l *models.League) { l.Teams = -1 }), wantErr: true},
+		{name: "negative budget", league: valid(func(l *models.League) { l.Budget = -5 }), wantErr: true},
+		{name: "slot without a position", league: valid(func(l *models.League) { l.Roster[0].Position = "" }), wantErr: true},
+		{name: "empty slot", league: valid(func(l *models.League) { l.Roster[1].Count = 0 }), wantErr: true},
+		{name: "bad scoring", league: valid(func(l *models.League) { l.Settings.Rules[0].Stat = "XBH" }), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateLeague(tt.league); (err != nil) != tt.wantErr {
+				t.Errorf("validateLeague = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBindProjectionRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	bind := func(form url.Values, request projectionRequest) (int, bool) {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest("POST", "/api/v1/baseball/export", strings.NewReader(form.Encode()))
+		c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		_, ok := bindProjectionRequest(c, request)
+		return recorder.Code, ok
+	}
+
+	for name, form := range map[string]url.Values{
+		"no settings":    {},
+		"bad json":       {"settings": {`{"year": 2025`}},
+		"unknown preset": {"settings": {`{"preset": "nope"}`}},
+	} {
+		if code, ok := bind(form, &models.ProjectionRequest{}); ok || code != http.StatusBadRequest {
+			t.Errorf("%s: ok = %v, status %d; want a 400", name, ok, code)
+		}
+	}
+
+	// An endpoint's own settings bind alongside the shared ones, and the preset is merged under them
+	var request models.ExportRequest
+	settings := `{"year": "2025", "preset": "ESPN", "group_by": "team", "settings": {"rules": [{"position": "pitcher", "stat": "SV", "weight": 7}]}}`
+	if _, ok := bind(url.Values{"settings": {settings}}, &request); !ok {
+		t.Fatal("a valid request was rejected")
+	}
+	if request.Year != "2025" || request.GroupBy != "team" {
+		t.Errorf("bound %+v, want year 2025 grouped by team", request)
+	}
+	espn, _ := models.LookupPreset("espn")
+	rules := request.Settings.ScoringRules()
+	if len(rules) != len(espn.Settings.Rules) {
+		t.Errorf("merged %d rules, want the preset's %d", len(rules), len(espn.Settings.Rules))
+	}
+	for _, rule := range rules {
+		if rule.Stat == "SV" && rule.Weight != 7 {
+			t.Errorf("SV weight = %v, want the override's 7", rule.Weight)
+		}
+	}
+}
+
+func TestApplyLeague(t *testing.T) {
+	rules := []models.ScoringRule{{Position: "batter", Stat: "HR", Weight: 4}}
+	league := models.League{ID: "l1", Teams: 10, Settings: models.LeagueSettings{Rules: rules}}
+
+	request := models.ProjectionRequest{Settings: models.LeagueSettings{Rules: []models.ScoringRule{{Position: "batter", Stat: "R", Weight: 1}}}}
+	applyLeague(&request, league)
+	if request.LeagueID != "l1" || len(request.Settings.Rules) != 1 || request.Settings.Rules[0].Stat != "HR" || request.Categories != nil {
+		t.Errorf("points league applied as %+v", request)
+	}
+
+	// A categories league plays its categories with its own team count unless they set one
+	league.Categories = &models.CategorySettings{Method: "sgp"}
+	request = models.ProjectionRequest{}
+	applyLeague(&request, league)
+	if request.Categories == nil || request.Categories.Method != "sgp" || request.Categories.Teams != 10 {
+		t.Errorf("categories = %+v, want sgp for 10 teams", request.Categories)
+	}
+	if league.Categories.Teams != 0 {
+		t.Error("applyLeague changed the saved league's categories")
+	}
+	league.Categories.Teams = 14
+	applyLeague(&request, league)
+	if request.Categories.Teams != 14 {
+		t.Errorf("categories teams = %d, want the categories' own 14", request.Categories.Teams)
+	}
+}
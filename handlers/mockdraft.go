@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// callerTeam is the caller's team in a mock draft
+const callerTeam = "You"
+
+// RunMockDraft runs a snake or auction mock draft of the stored players between bots and the
+// caller's team. Players are valued (and priced) like the export, with the league's teams,
+// roster and budget read like auction terms, and ADP from the upload unless the settings have
+// their own. The caller's picks come from "mock_draft"; when they run out the draft stops at
+// the caller's next turn and lists the best available, so a draft is played by sending the
+// same settings again with one more pick.
+func RunMockDraft(c *gin.Context) {
+	var request models.MockDraftRequest
+	league, ok := bindProjectionRequest(c, &request)
+	if !ok {
+		return
+	}
+	valuation, ok := newValuation(c, request.ProjectionRequest)
+	if !ok {
+		return
+	}
+	auction, ok := newAuction(c, request.Auction, league)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := loadPlayerPool(ctx, request.ProjectionRequest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return
+	}
+	_, rows := valuation.rows(pool, false)
+	sortRows(rows)
+
+	// ADP comes from the settings, or else the uploaded ADP
+	var table adpTable
+	if request.MockDraft.ADP == nil {
+		if table, _, err = loadADPTable(ctx, request.ProjectionRequest, request.ADP); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load ADP: " + err.Error()})
+			return
+		}
+	}
+
+	roster := rosterPlayers(rows, request.Eligibility)
+	prices := priceRows(auction, rows, request.Eligibility)
+	players := make([]baseball.MockPlayer, len(rows))
+	byID := make(map[string]int)
+	for i := len(rows) - 1; i >= 0; i-- {
+		id := rows[i].player.DraftID()
+		adp := request.MockDraft.ADP[id]
+		if table != nil {
+			adp, _ = table.of(rows[i].player)
+		}
+		players[i] = baseball.MockPlayer{
+			RosterPlayer: roster[i],
+			ID:           id,
+			Name:         rows[i].player.Name,
+			ADP:          adp,
+			Dollars:      prices[i].Dollars,
+		}
+		byID[id] = i
+	}
+
+	draft, err := baseball.NewMockDraft(request.MockDraft, auction.Settings(), players)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid mock draft: " + err.Error()})
+		return
+	}
+	var picks []baseball.MockSelection
+	for _, pick := range request.MockDraft.Picks {
+		index, ok := byID[pick.PlayerID]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid pick: unknown player %q", pick.PlayerID)})
+			return
+		}
+		picks = append(picks, baseball.MockSelection{Player: index, Price: pick.Price})
+	}
+	outcome, err := draft.Run(picks)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pick: " + err.Error()})
+		return
+	}
+
+	teamName := func(seat int) string {
+		if seat == draft.Caller() {
+			return callerTeam
+		}
+		return fmt.Sprintf("Bot %d", seat+1)
+	}
+	pickResult := func(pick baseball.MockDraftPick) models.MockDraftPick {
+		row := rows[pick.Player]
+		return models.MockDraftPick{
+			Number:     pick.Number,
+			Team:       teamName(pick.Team),
+			PlayerID:   players[pick.Player].ID,
+			PlayerName: row.player.Name,
+			Position:   row.player.Position,
+			Slot:       pick.Slot,
+			Value:      row.value,
+			Price:      pick.Price,
+		}
+	}
+
+	result := models.MockDraftResult{Complete: outcome.Complete, Picks: []models.MockDraftPick{}}
+	taken := make(map[string]bool)
+	for _, pick := range outcome.Picks {
+		result.Picks = append(result.Picks, pickResult(pick))
+		taken[players[pick.Player].ID] = true
+	}
+	for _, team := range outcome.Teams {
+		summary := models.MockDraftTeam{Team: teamName(team.Seat), Strategy: team.Strategy, Picks: []models.MockDraftPick{}, Spent: team.Spent}
+		for _, pick := range team.Picks {
+			summary.Picks = append(summary.Picks, pickResult(pick))
+			if pick.Slot == baseball.BenchLabel {
+				summary.Bench += rows[pick.Player].value
+			} else {
+				summary.Projected += rows[pick.Player].value
+			}
+		}
+		result.Teams = append(result.Teams, summary)
+	}
+	if !outcome.Complete {
+		for i, player := range draft.Players() {
+			if len(result.Available) == bestAvailable {
+				break
+			}
+			if taken[player.ID] {
+				continue
+			}
+			available := models.DraftPlayer{
+				PlayerID:   player.ID,
+				PlayerName: player.Name,
+				Position:   rows[i].player.Position,
+				Team:       rows[i].player.Team,
+				Eligible:   player.Eligible,
+				Value:      player.Value,
+			}
+			if request.MockDraft.Type == models.DraftAuction {
+				available.Dollars = player.Dollars
+			}
+			result.Available = append(result.Available, available)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mock_draft": result})
+}
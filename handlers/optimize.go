@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OptimizeRoster finds the starting lineup with the most projected value one team can buy
+// with an auction budget, or draft with its snake picks, from the stored players. Players are
+// valued (and priced) like the export, with the league's teams and roster read like auction
+// terms; snake drafts take players to be there at any pick up to their uploaded ADP. A
+// two-way player is considered once, as their more valuable half.
+func OptimizeRoster(c *gin.Context) {
+	var request models.OptimizeRequest
+	league, ok := bindProjectionRequest(c, &request)
+	if !ok {
+		return
+	}
+	valuation, ok := newValuation(c, request.ProjectionRequest)
+	if !ok {
+		return
+	}
+	auction, ok := newAuction(c, request.Auction, league)
+	if !ok {
+		return
+	}
+	optimizer, err := baseball.NewOptimizer(request.Optimize, auction.Settings())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid optimize settings: " + err.Error()})
+		return
+	}
+	settings := optimizer.Settings()
+	snake := len(settings.Picks) > 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := loadPlayerPool(ctx, request.ProjectionRequest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return
+	}
+	var table adpTable
+	if snake {
+		if table, _, err = loadADPTable(ctx, request.ProjectionRequest, request.ADP); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load ADP: " + err.Error()})
+			return
+		}
+		if len(table) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No ADP has been uploaded for these settings"})
+			return
+		}
+	}
+	_, rows := valuation.rows(pool, false)
+	sortRows(rows)
+
+	roster := rosterPlayers(rows, request.Eligibility)
+	prices := priceRows(auction, rows, request.Eligibility)
+	var players []baseball.OptimizerPlayer
+	var indexes []int
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		if row.player.ID != "" && seen[row.player.ID] {
+			continue
+		}
+		seen[row.player.ID] = true
+		player := baseball.OptimizerPlayer{RosterPlayer: roster[i], Dollars: prices[i].Dollars}
+		if table != nil {
+			player.ADP, _ = table.of(row.player)
+		}
+		players = append(players, player)
+		indexes = append(indexes, i)
+	}
+
+	solution := optimizer.Solve(players)
+	result := models.OptimalRoster{
+		Type:      models.DraftAuction,
+		Projected: solution.Projected,
+		Lineup:    []models.RosterSpot{},
+		Optimal:   solution.Optimal,
+	}
+	if snake {
+		result.Type, result.Picks = models.DraftSnake, settings.Picks
+	} else {
+		result.Budget, result.Spent = settings.Budget, solution.Spent
+	}
+	for _, spot := range solution.Spots {
+		if spot.Player < 0 {
+			result.Open = append(result.Open, spot.Slot)
+			continue
+		}
+		row := rows[indexes[spot.Player]]
+		result.Lineup = append(result.Lineup, models.RosterSpot{
+			Slot:       spot.Slot,
+			PlayerID:   row.player.ID,
+			PlayerName: row.player.Name,
+			Position:   row.player.Position,
+			Team:       row.player.Team,
+			Value:      row.value,
+			Price:      spot.Price,
+			Pick:       spot.Pick,
+			ADP:        players[spot.Player].ADP,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roster": result})
+}
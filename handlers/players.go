@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"super-fantasy-api/db"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPlayers searches the canonical player registry by name and team
+func ListPlayers(c *gin.Context) {
+	players, err := db.FindPlayers(c.Query("name"), c.Query("team"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to query players: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"players": players})
+}
+
+// AddPlayerAlias links a source's spelling of a name to a registry player, so future
+// uploads (including rows reported as unmatched) resolve to it
+func AddPlayerAlias(c *gin.Context) {
+	var alias models.PlayerAlias
+	if err := c.ShouldBindJSON(&alias); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alias format: " + err.Error()})
+		return
+	}
+	if strings.TrimSpace(alias.Name) == "" || alias.PlayerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Alias needs a name and a player_id"})
+		return
+	}
+
+	if err := db.SavePlayerAlias(alias); err != nil {
+		if errors.Is(err, db.ErrPlayerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save alias: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Alias saved successfully"})
+}
+
+// ImportCrosswalk loads a player ID crosswalk CSV (SFBB Player ID Map, Chadwick register)
+// into the registry
+func ImportCrosswalk(c *gin.Context) {
+	file, _, err := c.Request.FormFile("csv")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to get CSV file: " + err.Error()})
+		return
+	}
+	defer file.Close()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, file); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read CSV: " + err.Error()})
+		return
+	}
+
+	report, err := db.ImportCrosswalk(buf.String())
+	if err != nil {
+		respondCSVError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Crosswalk imported successfully", "report": report})
+}
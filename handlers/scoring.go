@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ValidateScoring checks a league's scoring settings: rules must name known stats and parse,
+// and every rule a registered source can't score (it doesn't project a stat the rule needs)
+// is listed, so leagues can see which sources will come up short before projecting
+func ValidateScoring(c *gin.Context) {
+	var settings models.LeagueSettings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid league settings format: " + err.Error()})
+		return
+	}
+
+	scorer, err := baseball.NewScorer(settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scoring rules: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": settings.ScoringRules(), "unscorable": scorer.Coverage()})
+}
+
+// ListPresets returns the built-in scoring presets
+func ListPresets(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"presets": models.Presets})
+}
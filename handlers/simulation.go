@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SimulatePlayerPoints runs Monte Carlo seasons for every stored player and reports the spread
+// of their fantasy points: mean, P10, P50 and P90. Seasons are sampled around the player's
+// projection combined stat by stat, varying each stat by how much the sources disagree on it
+// and all of them by playing-time risk. Settings come from "simulation"; a seed repeats a run.
+func SimulatePlayerPoints(c *gin.Context) {
+	var request models.SimulationRequest
+	if _, ok := bindProjectionRequest(c, &request); !ok {
+		return
+	}
+	if request.Categories != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Simulation scores fantasy points, which categories leagues don't have"})
+		return
+	}
+	valuation, ok := newValuation(c, request.ProjectionRequest)
+	if !ok {
+		return
+	}
+	simulation, err := baseball.NewSimulation(request.Simulation, valuation.scorer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid simulation: " + err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := loadPlayerPool(ctx, request.ProjectionRequest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return
+	}
+
+	var players []*poolPlayer
+	var simulated []baseball.SimulatedPlayer
+	for key, player := range pool.Players {
+		var lines []baseball.Line
+		for _, line := range player.SourceLines() {
+			if valuation.aggregator.Weight(line.Source) > 0 {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		players = append(players, player)
+		simulated = append(simulated, baseball.SimulatedPlayer{
+			Key:   key,
+			Line:  valuation.aggregator.Line(lines),
+			Lines: lines,
+		})
+	}
+	results := simulation.Run(simulated)
+
+	outcomes := make([]models.PlayerOutcome, len(results))
+	for i, result := range results {
+		outcomes[i] = models.PlayerOutcome{
+			PlayerName: players[i].Name,
+			Position:   players[i].Position,
+			Team:       players[i].Team,
+			Projected:  valuation.scorer.Score(simulated[i].Line).TotalPoints,
+			Mean:       result.Mean,
+			P10:        result.P10,
+			P50:        result.P50,
+			P90:        result.P90,
+		}
+	}
+	sort.SliceStable(outcomes, func(i, j int) bool {
+		if outcomes[i].P50 != outcomes[j].P50 {
+			return outcomes[i].P50 > outcomes[j].P50
+		}
+		return outcomes[i].PlayerName < outcomes[j].PlayerName
+	})
+
+	c.JSON(http.StatusOK, gin.H{"players": outcomes})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTiering compiles the request's tier settings, answering 400 when they're invalid
+func newTiering(c *gin.Context, settings *models.TierSettings) (*baseball.Tiering, bool) {
+	var terms models.TierSettings
+	if settings != nil {
+		terms = *settings
+	}
+	tiering, err := baseball.NewTiering(terms)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tiers: " + err.Error()})
+		return nil, false
+	}
+	return tiering, true
+}
+
+// tierRows tiers export rows by their value. Rows are first sorted (see sortRows), so players
+// with equal values are always tiered the same way.
+func tierRows(tiering *baseball.Tiering, rows []exportRow, eligibility models.EligibilitySettings) []baseball.Tier {
+	sortRows(rows)
+	return tiering.Tiers(rosterPlayers(rows, eligibility))
+}
+
+// tierCells labels each row with its tier at its first tier position ("SS 2"), blank for
+// players below the tiered depth there
+func tierCells(rows []exportRow, tiers []baseball.Tier, eligibility models.EligibilitySettings) []string {
+	players := rosterPlayers(rows, eligibility)
+	cells := make([]string, len(rows))
+	for _, tier := range tiers {
+		for _, index := range tier.Players {
+			if baseball.TierPositions(players[index])[0] == tier.Position {
+				cells[index] = fmt.Sprintf("%s %d", tier.Position, tier.Number)
+			}
+		}
+	}
+	return cells
+}
+
+// PlayerTiers groups every stored player into draft tiers by position, from the same
+// aggregate points (or category values) as the export. Players are tiered at each position
+// they're eligible at; "tiers" in the settings sets the tier count, per position if needed,
+// and how many players deep each position is tiered.
+func PlayerTiers(c *gin.Context) {
+	var request models.TiersRequest
+	if _, ok := bindProjectionRequest(c, &request); !ok {
+		return
+	}
+	valuation, ok := newValuation(c, request.ProjectionRequest)
+	if !ok {
+		return
+	}
+	tiering, ok := newTiering(c, request.Tiers)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := loadPlayerPool(ctx, request.ProjectionRequest)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return
+	}
+	_, rows := valuation.rows(pool, false)
+
+	tiers := tierRows(tiering, rows, request.Eligibility)
+	results := make([]models.PositionTier, len(tiers))
+	for i, tier := range tiers {
+		results[i] = models.PositionTier{
+			Position: tier.Position,
+			Tier:     tier.Number,
+			High:     tier.High,
+			Low:      tier.Low,
+		}
+		for _, index := range tier.Players {
+			results[i].Players = append(results[i].Players, models.TieredPlayer{
+				PlayerName: rows[index].player.Name,
+				Team:       rows[index].player.Team,
+				Value:      rows[index].value,
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tiers": results})
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"super-fantasy-api/data/baseball"
+	"super-fantasy-api/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// valuation is how a request values players: points from its scoring rules, or category
+// values for a categories league
+type valuation struct {
+	scorer     *baseball.Scorer
+	valuer     *baseball.CategoryValuer
+	aggregator *baseball.Aggregator
+}
+
+// newValuation compiles the request's scoring rules, or its categories, and how sources are
+// aggregated, answering 400 when they don't compile
+func newValuation(c *gin.Context, request models.ProjectionRequest) (valuation, bool) {
+	aggregator, err := baseball.NewAggregator(request.Aggregation)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid aggregation: " + err.Error()})
+		return valuation{}, false
+	}
+	if request.Categories != nil {
+		valuer, err := baseball.NewCategoryValuer(*request.Categories)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid categories: " + err.Error()})
+			return valuation{}, false
+		}
+		return valuation{valuer: valuer, aggregator: aggregator}, true
+	}
+	scorer, err := baseball.NewScorer(request.Settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scoring rules: " + err.Error()})
+		return valuation{}, false
+	}
+	return valuation{scorer: scorer, aggregator: aggregator}, true
+}
+
+// rows values every pool player, with the export columns for the valuation. Category values
+// always combine sources stat by stat.
+func (v valuation) rows(pool *playerPool, breakdown bool) ([]string, []exportRow) {
+	if v.valuer != nil {
+		return categoryRows(pool, v.valuer, v.aggregator)
+	}
+	return pointsRows(pool, v.scorer, v.aggregator, breakdown)
+}
+
+// sortRows puts rows best first. The pool comes back in no particular order, so ties break by
+// name and then position, giving the same order every time.
+func sortRows(rows []exportRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.value != b.value {
+			return a.value > b.value
+		}
+		if a.player.Name != b.player.Name {
+			return a.player.Name < b.player.Name
+		}
+		return a.player.Position < b.player.Position
+	})
+}
+
+// newAuction builds the auction a request prices players with: its own auction terms, with
+// what they leave zero taken from the saved league. Answers 400 for invalid terms.
+func newAuction(c *gin.Context, terms *models.AuctionSettings, league *models.League) (*baseball.Auction, bool) {
+	var settings models.AuctionSettings
+	if terms != nil {
+		settings = *terms
+	}
+	if league != nil {
+		if settings.Teams == 0 {
+			settings.Teams = league.Teams
+		}
+		if settings.Budget == 0 {
+			settings.Budget = league.Budget
+		}
+		if len(settings.Roster) == 0 {
+			settings.Roster = league.Roster
+		}
+	}
+
+	auction, err := baseball.NewAuction(settings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid auction settings: " + err.Error()})
+		return nil, false
+	}
+	return auction, true
+}
+
+// rosterPlayers pairs export rows' values with the players' eligibility
+func rosterPlayers(rows []exportRow, eligibility models.EligibilitySettings) []baseball.RosterPlayer {
+	players := make([]baseball.RosterPlayer, len(rows))
+	for i, row := range rows {
+		players[i] = baseball.RosterPlayer{
+			Position: strings.ToLower(row.player.Position),
+			Eligible: row.player.Eligible(eligibility),
+			Value:    row.value,
+		}
+	}
+	return players
+}
+
+// priceRows prices export rows by their value
+func priceRows(auction *baseball.Auction, rows []exportRow, eligibility models.EligibilitySettings) []baseball.AuctionValue {
+	prices, _ := auction.Value(rosterPlayers(rows, eligibility))
+	return prices
+}
+
+// PlayerValues prices every stored player for an auction draft: replacement level at each
+// position, value above replacement and dollars, from the same aggregate points (or category
+// values) as the export. Auction terms come from "auction" in the settings and the saved league.
+func PlayerValues(c *gin.Context) {
+	var request models.ProjectionRequest
+	league, ok := bindProjectionRequest(c, &request)
+	if !ok {
+		return
+	}
+	valuation, ok := newValuation(c, request)
+	if !ok {
+		return
+	}
+	auction, ok := newAuction(c, request.Auction, league)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := loadPlayerPool(ctx, request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return
+	}
+	_, rows := valuation.rows(pool, false)
+	players := rosterPlayers(rows, request.Eligibility)
+	prices, levels := auction.Value(players)
+
+	values := make([]models.PlayerValue, len(rows))
+	for i, row := range rows {
+		values[i] = models.PlayerValue{
+			PlayerName:       row.player.Name,
+			Position:         row.player.Position,
+			Team:             row.player.Team,
+			Eligible:         players[i].Eligible,
+			Sources:          row.sources,
+			Value:            row.value,
+			Slot:             prices[i].Slot,
+			Replacement:      prices[i].Replacement,
+			AboveReplacement: prices[i].AboveReplacement,
+			Dollars:          prices[i].Dollars,
+			Rostered:         prices[i].Rostered,
+		}
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		if values[i].Dollars != values[j].Dollars {
+			return values[i].Dollars > values[j].Dollars
+		}
+		return values[i].PlayerName < values[j].PlayerName
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"values":      values,
+		"replacement": levels,
+		"auction":     auction.Settings(),
+	})
+}
+
+// PlayerRankings ranks every stored player by value over replacement (VORP) for a league's
+// roster. The league's starting slots are filled to make the starters' total value as high
+// as possible, and each player is measured against the replacement level at their scarcest
+// eligible slot. Roster and team count are read like auction terms.
+func PlayerRankings(c *gin.Context) {
+	var request models.ProjectionRequest
+	league, ok := bindProjectionRequest(c, &request)
+	if !ok {
+		return
+	}
+	valuation, ok := newValuation(c, request)
+	if !ok {
+		return
+	}
+	auction, ok := newAuction(c, request.Auction, league)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := loadPlayerPool(ctx, request)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load projections: " + err.Error()})
+		return
+	}
+	_, rows := valuation.rows(pool, false)
+	players := rosterPlayers(rows, request.Eligibility)
+	fits, levels := auction.Roster().Fill(players)
+
+	rankings := make([]models.PlayerValue, len(rows))
+	for i, row := range rows {
+		rankings[i] = models.PlayerValue{
+			PlayerName:       row.player.Name,
+			Position:         row.player.Position,
+			Team:             row.player.Team,
+			Eligible:         players[i].Eligible,
+			Sources:          row.sources,
+			Value:            row.value,
+			Slot:             fits[i].Slot,
+			Replacement:      fits[i].Replacement,
+			AboveReplacement: row.value - fits[i].Replacement,
+			Rostered:         fits[i].Slot != "",
+		}
+	}
+	sort.SliceStable(rankings, func(i, j int) bool {
+		if rankings[i].AboveReplacement != rankings[j].AboveReplacement {
+			return rankings[i].AboveReplacement > rankings[j].AboveReplacement
+		}
+		return rankings[i].PlayerName < rankings[j].PlayerName
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"rankings":    rankings,
+		"replacement": levels,
+	})
+}
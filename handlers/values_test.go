@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSortRows(t *testing.T) {
+	row := func(name, position string, value float64) exportRow {
+		return exportRow{player: &poolPlayer{Name: name, Position: position}, value: value}
+	}
+	rows := []exportRow{
+		row("Shohei Ohtani", "Pitcher", 300),
+		row("Aaron Judge", "Batter", 250),
+		row("Shohei Ohtani", "Batter", 300),
+		row("Bobby Witt", "Batter", 300),
+		row("Juan Soto", "Batter", 280),
+	}
+	sortRows(rows)
+	var got []string
+	for _, r := range rows {
+		got = append(got, r.player.Name+" "+r.player.Position)
+	}
+	want := []string{"Bobby Witt Batter", "Shohei Ohtani Batter", "Shohei Ohtani Pitcher", "Juan Soto Batter", "Aaron Judge Batter"}
+	if !slices.Equal(got, want) {
+		t.Errorf("sortRows = %v, want %v", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"strings"
 	"super-fantasy-api/db"
 	"super-fantasy-api/handlers"
 
@@ -25,6 +26,18 @@ func main() {
 	if err := db.InitMongoDB(mongoURI, dbName, collectionName); err != nil {
 		log.Fatal("Failed to initialize MongoDB:", err)
 	}
+
+	// One-off commands run against the database instead of serving the API
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1:])
+		return
+	}
+
+	// Browser origins besides the API's own that may follow draft rooms, comma separated
+	handlers.AllowOrigins(strings.FieldsFunc(os.Getenv("ALLOWED_ORIGINS"), func(r rune) bool {
+		return r == ',' || r == ' '
+	}))
+
 	router := gin.Default()
 
 	// API Versioning
@@ -46,8 +59,40 @@ func main() {
 		baseball := v1.Group("/baseball")
 		baseball.POST("/projections", handlers.CalculateBaseballProjections)
 		baseball.POST("/export", handlers.ExportPlayerPointsCSV)
+		baseball.POST("/values", handlers.PlayerValues)
+		baseball.POST("/rankings", handlers.PlayerRankings)
+		baseball.POST("/disagreement", handlers.PlayerDisagreement)
+		baseball.POST("/tiers", handlers.PlayerTiers)
+		baseball.POST("/simulate", handlers.SimulatePlayerPoints)
+		baseball.POST("/mock-draft", handlers.RunMockDraft)
+		baseball.POST("/adp", handlers.PlayerADP)
+		baseball.POST("/optimize", handlers.OptimizeRoster)
 		uploadBaseball := baseball.Group("/upload")
 		uploadBaseball.POST("", handlers.UploadCSV)
+		baseball.GET("/sources", handlers.ListSources)
+		baseball.POST("/scoring/validate", handlers.ValidateScoring)
+		baseball.GET("/presets", handlers.ListPresets)
+		baseball.GET("/teams", handlers.ListTeams)
+		baseball.GET("/players", handlers.ListPlayers)
+		baseball.POST("/players/aliases", handlers.AddPlayerAlias)
+		baseball.POST("/players/crosswalk", handlers.ImportCrosswalk)
+
+		// Saved league profiles
+		leagues := v1.Group("/leagues")
+		leagues.POST("", handlers.CreateLeague)
+		leagues.GET("", handlers.ListLeagues)
+		leagues.GET("/:id", handlers.GetLeague)
+		leagues.PUT("/:id", handlers.UpdateLeague)
+		leagues.DELETE("/:id", handlers.DeleteLeague)
+
+		// Draft rooms
+		drafts := v1.Group("/drafts")
+		drafts.POST("", handlers.CreateDraft)
+		drafts.GET("/:id", handlers.GetDraftBoard)
+		drafts.DELETE("/:id", handlers.DeleteDraft)
+		drafts.POST("/:id/picks", handlers.MakeDraftPick)
+		drafts.DELETE("/:id/picks/last", handlers.UndoDraftPick)
+		drafts.GET("/:id/ws", handlers.DraftSocket)
 	}
 
 	router.Run(":8080")
@@ -0,0 +1,59 @@
+package models
+
+// Upload types
+const (
+	UploadProjections = "projections" // a projection system's batter or pitcher export (default)
+	UploadADP         = "adp"         // an average draft position export
+)
+
+// ADPEntry is one player's average draft position from an uploaded ADP export. It's also the
+// document stored for the row.
+type ADPEntry struct {
+	Name      string  `bson:"name" json:"name"`
+	Team      string  `bson:"team" json:"team"`
+	Positions string  `bson:"positions,omitempty" json:"positions,omitempty"`
+	Rank      int     `bson:"rank" json:"rank"` // the export's rank, or the row's order when it has none
+	ADP       float64 `bson:"adp" json:"adp"`
+	MinPick   float64 `bson:"min_pick,omitempty" json:"min_pick,omitempty"`
+	MaxPick   float64 `bson:"max_pick,omitempty" json:"max_pick,omitempty"`
+	Year      string  `bson:"year" json:"year"`
+	Source    string  `bson:"source" json:"source"`                           // ADP format, e.g. "nfbc"
+	PlayerID  string  `bson:"player_id,omitempty" json:"player_id,omitempty"` // canonical player ID from the registry
+}
+
+// ADPRequest configures /baseball/adp
+type ADPRequest struct {
+	ProjectionRequest
+	ADP *ADPSettings `json:"adp,omitempty"`
+}
+
+// ADPSettings pick the uploaded ADP that players' values are compared against
+type ADPSettings struct {
+	Source string `json:"source,omitempty"` // "nfbc", "fantasypros" or "yahoo"; default every uploaded one, averaged
+	Year   string `json:"year,omitempty"`   // default the request's year, else the latest uploaded
+	Limit  int    `json:"limit,omitempty"`  // players in each list of the report, default 25
+}
+
+// ADPReport lists the players whose value rank is furthest from their ADP rank. Both ranks
+// count only the players that have a value and an ADP.
+type ADPReport struct {
+	Source     string          `json:"source,omitempty"`
+	Year       string          `json:"year"`
+	Players    int             `json:"players"`     // players with a value and an ADP
+	ValuePicks []ADPComparison `json:"value_picks"` // drafted well after their value rank
+	Overdrafts []ADPComparison `json:"overdrafts"`  // drafted well before it
+}
+
+// ADPComparison is a player's value rank against their ADP rank. Difference is the ADP rank
+// less the value rank: positive for value picks, negative for overdrafts.
+type ADPComparison struct {
+	PlayerID   string  `json:"player_id,omitempty"`
+	PlayerName string  `json:"player_name"`
+	Position   string  `json:"position"`
+	Team       string  `json:"team"`
+	Value      float64 `json:"value"`
+	ValueRank  int     `json:"value_rank"`
+	ADP        float64 `json:"adp"`
+	ADPRank    int     `json:"adp_rank"`
+	Difference int     `json:"difference"`
+}
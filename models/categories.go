@@ -0,0 +1,44 @@
+package models
+
+// Valuation methods for categories leagues
+const (
+	MethodZScore = "zscore" // standard deviations above the player pool, summed over categories
+	MethodSGP    = "sgp"    // Standings Gain Points: standings places gained, summed over categories
+)
+
+// Category is one roto or head-to-head category. Counting stats (HR, SB) count as projected;
+// rate stats (AVG, ERA) are weighted by the playing time in Volume, so a .300 hitter over 600
+// at bats is worth more than one over 200.
+type Category struct {
+	Position string  `json:"position"`         // "batter" or "pitcher"
+	Stat     string  `json:"stat"`             // stat the category totals, e.g. "HR" or "ERA"
+	Volume   string  `json:"volume,omitempty"` // playing-time stat a rate is weighted by ("AB" for AVG, "IP" for ERA)
+	Lower    bool    `json:"lower,omitempty"`  // lower is better; implied for ERA, WHIP, L and BS
+	SGP      float64 `json:"sgp,omitempty"`    // amount of the stat worth one standings place
+}
+
+// CategorySettings configures a categories valuation. Teams and the lineup sizes set the
+// player pool values are measured against: the players a league's teams would roster.
+type CategorySettings struct {
+	Method     string     `json:"method,omitempty"`     // "zscore" (default) or "sgp"
+	Categories []Category `json:"categories,omitempty"` // defaults to 5x5 roto
+	Teams      int        `json:"teams,omitempty"`      // teams in the league, default 12
+	Hitters    int        `json:"hitters,omitempty"`    // batters rostered per team, default 13
+	Pitchers   int        `json:"pitchers,omitempty"`   // pitchers rostered per team, default 9
+}
+
+// RotoCategories is standard 5x5 roto: R, HR, RBI, SB, AVG and W, SV, K, ERA, WHIP. The SGP
+// denominators are typical of 12-team leagues; leagues with their own standings history
+// should set theirs.
+var RotoCategories = []Category{
+	{Position: "batter", Stat: "R", SGP: 20},
+	{Position: "batter", Stat: "HR", SGP: 7},
+	{Position: "batter", Stat: "RBI", SGP: 20},
+	{Position: "batter", Stat: "SB", SGP: 6},
+	{Position: "batter", Stat: "AVG", Volume: "AB", SGP: 0.002},
+	{Position: "pitcher", Stat: "W", SGP: 2.5},
+	{Position: "pitcher", Stat: "SV", SGP: 7},
+	{Position: "pitcher", Stat: "SO", SGP: 25},
+	{Position: "pitcher", Stat: "ERA", Volume: "IP", Lower: true, SGP: 0.06},
+	{Position: "pitcher", Stat: "WHIP", Volume: "IP", Lower: true, SGP: 0.012},
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Draft types
+const (
+	DraftSnake   = "snake"
+	DraftAuction = "auction"
+)
+
+// Draft is a draft for a saved league and the picks made in it so far. Its terms are fixed
+// from the league when it's created.
+type Draft struct {
+	ID       string   `bson:"_id" json:"id"`
+	LeagueID string   `bson:"league_id" json:"league_id"`
+	Name     string   `bson:"name" json:"name"`
+	Type     string   `bson:"type" json:"type"`   // "snake" or "auction"
+	Teams    []string `bson:"teams" json:"teams"` // team names, in first-round order for a snake draft
+	Spots    int      `bson:"spots" json:"spots"` // roster spots per team, bench included
+	// Budget and MinBid are the auction terms, per team
+	Budget    float64     `bson:"budget,omitempty" json:"budget,omitempty"`
+	MinBid    float64     `bson:"min_bid,omitempty" json:"min_bid,omitempty"`
+	Picks     []DraftPick `bson:"picks" json:"picks"`
+	CreatedAt time.Time   `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time   `bson:"updated_at" json:"updated_at"`
+}
+
+// DraftPick is one player taken in a draft
+type DraftPick struct {
+	Number     int       `bson:"number" json:"number"` // overall pick, from 1
+	Team       string    `bson:"team" json:"team"`
+	PlayerID   string    `bson:"player_id" json:"player_id"`
+	PlayerName string    `bson:"player_name" json:"player_name"`
+	Position   string    `bson:"position" json:"position"`
+	Price      float64   `bson:"price,omitempty" json:"price,omitempty"` // winning bid, for auctions
+	PickedAt   time.Time `bson:"picked_at" json:"picked_at"`
+}
+
+// DraftPlayer is a player available in a draft, valued the way the export values them
+type DraftPlayer struct {
+	PlayerID   string   `json:"player_id"` // registry ID, or "Name:Position" for players the registry hasn't matched
+	PlayerName string   `json:"player_name"`
+	Position   string   `json:"position"`
+	Team       string   `json:"team"`
+	Eligible   []string `json:"eligible,omitempty"`
+	Value      float64  `json:"value"`             // aggregate points, or the category total
+	Dollars    float64  `json:"dollars,omitempty"` // pre-draft auction price, for auctions
+}
+
+// TeamRoster is one team's picks and what's left to fill
+type TeamRoster struct {
+	Team      string      `json:"team"`
+	Picks     []DraftPick `json:"picks"`
+	Open      int         `json:"open"` // roster spots left
+	Spent     float64     `json:"spent,omitempty"`
+	Remaining float64     `json:"remaining,omitempty"`
+	MaxBid    float64     `json:"max_bid,omitempty"` // most the team can bid and still fill its roster
+}
+
+// DraftBoard is the state of a draft pushed to the draft room: the picks, who's on the clock in
+// a snake draft, the best players still available and every team's roster
+type DraftBoard struct {
+	Draft      Draft         `json:"draft"`
+	OnTheClock string        `json:"on_the_clock,omitempty"`
+	Available  []DraftPlayer `json:"available"`
+	Rosters    []TeamRoster  `json:"rosters"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// League is a saved league profile: its scoring plus the roster, team count and budget that
+// valuations need. Projection and export requests can name one with league_id instead of
+// sending settings inline.
+type League struct {
+	ID       string         `bson:"_id" json:"id"`
+	Name     string         `bson:"name" json:"name"`
+	Settings LeagueSettings `bson:"settings" json:"settings"`
+	// Categories makes this a categories league; its settings then go unused
+	Categories *CategorySettings `bson:"categories,omitempty" json:"categories,omitempty"`
+	Roster     []RosterSlot      `bson:"roster" json:"roster"`
+	Teams      int               `bson:"teams" json:"teams"`
+	Budget     float64           `bson:"budget" json:"budget"` // auction budget per team
+	CreatedAt  time.Time         `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time         `bson:"updated_at" json:"updated_at"`
+}
+
+// RosterSlot is a lineup position and how many of it each team starts ("OF" x 3, "UTIL", "BN")
+type RosterSlot struct {
+	Position string `bson:"position" json:"position"`
+	Count    int    `bson:"count" json:"count"`
+}
+
+// AuctionSettings are the league terms auction values are priced with. Fields left zero are
+// taken from the saved league the request names, then from the defaults: 12 teams, $260,
+// $1 minimum bids and DefaultRoster.
+type AuctionSettings struct {
+	Teams  int          `json:"teams,omitempty"`
+	Budget float64      `json:"budget,omitempty"`  // per team
+	MinBid float64      `json:"min_bid,omitempty"` // lowest bid, what every rostered player costs at least
+	Roster []RosterSlot `json:"roster,omitempty"`
+}
+
+// DefaultRoster is a standard 23-man roto roster
+var DefaultRoster = []RosterSlot{
+	{Position: "C", Count: 2},
+	{Position: "1B", Count: 1},
+	{Position: "2B", Count: 1},
+	{Position: "3B", Count: 1},
+	{Position: "SS", Count: 1},
+	{Position: "CI", Count: 1},
+	{Position: "MI", Count: 1},
+	{Position: "OF", Count: 5},
+	{Position: "UTIL", Count: 1},
+	{Position: "P", Count: 9},
+}
+
+// PlayerValue is one player's auction price: projected value, the replacement level at their
+// position, how far above it they are (VORP) and what that's worth in dollars
+type PlayerValue struct {
+	PlayerName       string   `json:"player_name"`
+	Position         string   `json:"position"`
+	Team             string   `json:"team"`
+	Eligible         []string `json:"eligible,omitempty"` // positions the player can fill
+	Sources          []string `json:"sources,omitempty"`  // sources the value combines
+	Value            float64  `json:"value"`              // aggregate points, or the category total
+	Slot             string   `json:"slot,omitempty"`     // starting slot the player fills in the league
+	Replacement      float64  `json:"replacement"`        // replacement level at their scarcest eligible slot
+	AboveReplacement float64  `json:"above_replacement"`
+	Dollars          float64  `json:"dollars,omitempty"`
+	Rostered         bool     `json:"rostered"` // one of the players the league's teams would roster
+}
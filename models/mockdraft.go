@@ -0,0 +1,77 @@
+package models
+
+// Bot strategies for mock drafts
+const (
+	StrategyADP    = "adp"    // best available by ADP
+	StrategyPoints = "points" // best available by projected value
+	StrategyNeed   = "need"   // best available at a starting slot the team still has open
+)
+
+// MockDraftRequest configures /baseball/mock-draft. Without ADP in the mock draft settings,
+// ADP picks the uploaded ADP.
+type MockDraftRequest struct {
+	ProjectionRequest
+	MockDraft MockDraftSettings `json:"mock_draft"`
+	ADP       *ADPSettings      `json:"adp,omitempty"`
+}
+
+// MockDraftSettings configure a mock draft against bots. The league's teams, roster and budget
+// come from the auction terms. The caller's team drafts the players in Picks, in order; the
+// draft stops at the caller's next turn once they run out, unless Autopick is set.
+type MockDraftSettings struct {
+	Type     string        `json:"type,omitempty"`     // "snake" (default) or "auction"
+	Slot     int           `json:"slot,omitempty"`     // the caller's first-round pick (or nomination turn), from 1, default 1
+	Strategy string        `json:"strategy,omitempty"` // bots' strategy, default "adp"
+	Noise    float64       `json:"noise,omitempty"`    // bots' randomness, as a share of each player's score or bid
+	Bots     []BotSettings `json:"bots,omitempty"`     // particular bots' strategy and noise, in seat order
+	Seed     int64         `json:"seed,omitempty"`     // random seed for the bots' noise
+	Picks    []MockPick    `json:"picks,omitempty"`    // the caller's picks, in order
+	Autopick bool          `json:"autopick,omitempty"` // finish the caller's picks by projected value
+	// ADP by player ID ("Name:Position" for players the registry hasn't matched); players
+	// without one are taken to go after those with one, by value
+	ADP map[string]float64 `json:"adp,omitempty"`
+}
+
+// BotSettings are one bot's strategy and noise, overriding the draft's where they're set
+type BotSettings struct {
+	Strategy string  `json:"strategy,omitempty"`
+	Noise    float64 `json:"noise,omitempty"`
+}
+
+// MockPick is a player the caller drafts: in an auction, Price is the most they'll bid
+type MockPick struct {
+	PlayerID string  `json:"player_id"`
+	Price    float64 `json:"price,omitempty"`
+}
+
+// MockDraftResult is how a mock draft went: every pick, every team's roster and projected
+// season total, and whether it ran to the end or stopped for the caller's next pick
+type MockDraftResult struct {
+	Complete  bool            `json:"complete"`
+	Picks     []MockDraftPick `json:"picks"`
+	Teams     []MockDraftTeam `json:"teams"`
+	Available []DraftPlayer   `json:"available,omitempty"` // best available, when the draft stopped for the caller
+}
+
+// MockDraftPick is a player taken in a mock draft and the roster slot they fill
+type MockDraftPick struct {
+	Number     int     `json:"number"`
+	Team       string  `json:"team"`
+	PlayerID   string  `json:"player_id"`
+	PlayerName string  `json:"player_name"`
+	Position   string  `json:"position"`
+	Slot       string  `json:"slot"`
+	Value      float64 `json:"value"`
+	Price      float64 `json:"price,omitempty"`
+}
+
+// MockDraftTeam is a team's mock draft roster. Projected totals the starters' values (season
+// points, or category totals); Bench totals the rest.
+type MockDraftTeam struct {
+	Team      string          `json:"team"`
+	Strategy  string          `json:"strategy,omitempty"` // the bot's strategy, empty for the caller
+	Picks     []MockDraftPick `json:"picks"`
+	Projected float64         `json:"projected"`
+	Bench     float64         `json:"bench"`
+	Spent     float64         `json:"spent,omitempty"`
+}
@@ -12,6 +12,7 @@ type LeagueSettings struct {
 	} `json:"batting"`
 	Pitching struct {
 		InningsPitched float64 `json:"innings_pitched"`
+		Outs           float64 `json:"outs"`
 		HitsAllowed    float64 `json:"hits_allowed"`
 		EarnedRuns     float64 `json:"earned_runs"`
 		WalksIssued    float64 `json:"walks_issued"`
@@ -23,24 +24,182 @@ type LeagueSettings struct {
 		Saves          float64 `json:"saves"`
 		Holds          float64 `json:"holds"`
 	} `json:"pitching"`
+	// Rules replace the batting and pitching weights above when present
+	Rules []ScoringRule `json:"rules,omitempty"`
 }
 
+// ProjectionRequest is what every baseball endpoint that values players reads from its
+// "settings" field: the scoring (inline, a saved league or a preset), which projections to
+// use and how to combine them. Endpoints with settings of their own embed it.
 type ProjectionRequest struct {
 	Settings       LeagueSettings `json:"settings"`
+	LeagueID       string         `json:"league_id,omitempty"` // use a saved league's settings
+	Preset         string         `json:"preset,omitempty"`    // host scoring preset the settings override
 	ProjectionName string         `json:"projection_name"`
 	Position       string         `json:"position"`
 	Year           string         `json:"year"`
 	Source         string         `json:"source"`
+	Teams          []string       `json:"teams,omitempty"`     // only include these teams (any source's codes)
+	Breakdown      bool           `json:"breakdown,omitempty"` // include each stat's points
+	// Categories values players for a roto or head-to-head categories league instead of points
+	Categories *CategorySettings `json:"categories,omitempty"`
+	// Auction prices players for an auction draft; the export adds a Dollars column
+	Auction *AuctionSettings `json:"auction,omitempty"`
+	// Eligibility sets the projected games players need to qualify at their positions
+	Eligibility EligibilitySettings `json:"eligibility,omitempty"`
+	// Aggregation sets how sources are combined into a player's aggregate
+	Aggregation AggregationSettings `json:"aggregation,omitempty"`
+}
+
+// Projection returns the shared part of a request, for the endpoint requests that embed it
+func (r *ProjectionRequest) Projection() *ProjectionRequest {
+	return r
+}
+
+// ExportRequest configures /baseball/export
+type ExportRequest struct {
+	ProjectionRequest
+	GroupBy string `json:"group_by,omitempty"` // "team" orders the export by canonical team
+	// Spread adds how much the sources disagree on each player's points
+	Spread           bool    `json:"spread,omitempty"`
+	OutlierThreshold float64 `json:"outlier_threshold,omitempty"` // share off the other sources' median, default 0.2
+	// Tiers groups players into tiers by position and adds a Tier column
+	Tiers *TierSettings `json:"tiers,omitempty"`
+	// ADP picks the uploaded ADP for the ADP columns
+	ADP *ADPSettings `json:"adp,omitempty"`
+}
+
+// DisagreementRequest configures /baseball/disagreement
+type DisagreementRequest struct {
+	ProjectionRequest
+	OutlierThreshold float64 `json:"outlier_threshold,omitempty"` // share off the other sources' median, default 0.2
+}
+
+// Aggregation methods and levels
+const (
+	AggregateMean    = "mean"    // weighted mean
+	AggregateMedian  = "median"  // middle value, ignoring weights other than 0
+	AggregateTrimmed = "trimmed" // weighted mean after dropping the highest and lowest values
+
+	LevelPoints = "points" // score each source, then combine the points
+	LevelStats  = "stats"  // combine each stat across sources, then score the combined line
+)
+
+// AggregationSettings configure how a player's projections from several sources combine
+type AggregationSettings struct {
+	Method  string             `json:"method,omitempty"`  // "mean" (default), "median" or "trimmed"
+	Level   string             `json:"level,omitempty"`   // "points" (default) or "stats"
+	Weights map[string]float64 `json:"weights,omitempty"` // by source name, default 1; 0 leaves a source out
+	Trim    float64            `json:"trim,omitempty"`    // share dropped from each end for "trimmed", default 0.2; at least one value of 3 or more
 }
 
 type PlayerProjection struct {
 	PlayerName  string  `json:"player_name"`
 	TotalPoints float64 `json:"total_points"`
+	Unscored    []Stat  `json:"unscored,omitempty"` // weighted stats the source doesn't project
+	// Breakdown has each scored stat's projected value and the points it contributes
+	Breakdown map[Stat]StatPoints `json:"breakdown,omitempty"`
+}
+
+// PlayerSpread is how much the sources projecting a player disagree on their points
+type PlayerSpread struct {
+	PlayerName string             `json:"player_name"`
+	Position   string             `json:"position"`
+	Team       string             `json:"team"`
+	Points     map[string]float64 `json:"points"` // by source label
+	Aggregate  float64            `json:"aggregate"`
+	Sources    int                `json:"sources"`
+	StdDev     float64            `json:"std_dev"`
+	Min        float64            `json:"min"`
+	Max        float64            `json:"max"`
+	Range      float64            `json:"range"`
+	Outliers   []string           `json:"outliers,omitempty"` // sources far from the rest, by label
+}
+
+// StatPoints is one stat's projected value and the points it's worth
+type StatPoints struct {
+	Value  float64 `json:"value"`
+	Points float64 `json:"points"`
 }
 
 type UploadRequest struct {
+	Type     string `json:"type,omitempty"` // "projections" (default) or "adp"
 	Source   string `json:"source"`
 	Position string `json:"position"`
 	Year     string `json:"year"`
 	Suffix   string `json:"suffix,omitempty"`
+	DryRun   bool   `json:"dry_run,omitempty"` // parse and report without saving
+	Preview  int    `json:"preview,omitempty"` // number of parsed players to include in the report
+}
+
+// UploadReport summarizes what was parsed from an uploaded projection CSV
+type UploadReport struct {
+	Rows       int            `json:"rows"`
+	Errors     []RowError     `json:"errors"`
+	BlankCells []BlankColumn  `json:"blank_cells"`
+	Duplicates []string       `json:"duplicates"`
+	Unmatched  []UnmatchedRow `json:"unmatched"`
+	Preview    []interface{}  `json:"preview"`
+}
+
+// RowError is a cell whose raw value could not be parsed as its column's type
+type RowError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column"`
+	Value  string `json:"value"`
+}
+
+// BlankColumn counts the empty cells found in one column
+type BlankColumn struct {
+	Column string `json:"column"`
+	Count  int    `json:"count"`
+}
+
+// Player is a canonical player in the identity registry that projection rows are linked to
+type Player struct {
+	ID      string   `bson:"_id" json:"id"`
+	Name    string   `bson:"name" json:"name"`
+	NameKey string   `bson:"name_key" json:"name_key"` // utils.NameKey of Name
+	Team    string   `bson:"team" json:"team"`         // team from the most recent year seen
+	Roles   []string `bson:"roles" json:"roles"`       // "batter" and/or "pitcher"
+	Years   []string `bson:"years" json:"years"`       // projection years the player appears in
+	// Positions are the positions the player is eligible at, from the latest year a source
+	// listed any (see ParsePositions)
+	Positions     []string `bson:"positions,omitempty" json:"positions,omitempty"`
+	PositionsYear string   `bson:"positions_year,omitempty" json:"positions_year,omitempty"`
+
+	PlayerIDs `bson:",inline"`
+}
+
+// PlayerIDs are a player's identifiers in external systems, loaded from a crosswalk file
+// or picked up from exports that carry them
+type PlayerIDs struct {
+	MLBAM       string `bson:"mlbam_id,omitempty" json:"mlbam_id,omitempty"`
+	FanGraphs   string `bson:"fangraphs_id,omitempty" json:"fangraphs_id,omitempty"`
+	FantasyPros string `bson:"fantasypros_id,omitempty" json:"fantasypros_id,omitempty"`
+}
+
+// PlayerAlias links a name as a source writes it to a canonical player
+type PlayerAlias struct {
+	Name     string `bson:"name" json:"name"`
+	NameKey  string `bson:"name_key" json:"-"`
+	Source   string `bson:"source,omitempty" json:"source,omitempty"` // empty applies to every source
+	PlayerID string `bson:"player_id" json:"player_id"`
+}
+
+// UnmatchedRow is an uploaded row that could not be linked to a single canonical player
+type UnmatchedRow struct {
+	Row        int      `json:"row"`
+	Name       string   `json:"name"`
+	Team       string   `json:"team"`
+	Candidates []Player `json:"candidates"`
+}
+
+// CrosswalkReport summarizes a player ID crosswalk import
+type CrosswalkReport struct {
+	Rows      int            `json:"rows"`
+	Matched   int            `json:"matched"`   // registry players the row's IDs were attached to
+	NotFound  int            `json:"not_found"` // rows for players the registry doesn't have
+	Skipped   int            `json:"skipped"`   // rows without any ID
+	Unmatched []UnmatchedRow `json:"unmatched"` // rows whose same-named players neither team nor role confirms
 }
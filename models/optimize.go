@@ -0,0 +1,44 @@
+package models
+
+// OptimizeRequest configures /baseball/optimize. ADP picks the uploaded ADP a snake draft is
+// optimized with.
+type OptimizeRequest struct {
+	ProjectionRequest
+	Optimize OptimizeSettings `json:"optimize"`
+	ADP      *ADPSettings     `json:"adp,omitempty"`
+}
+
+// OptimizeSettings are what one team has to build its roster with: an auction budget, or its
+// picks in a snake draft. Setting picks or a slot optimizes a snake draft; otherwise an auction.
+type OptimizeSettings struct {
+	Budget float64 `json:"budget,omitempty"` // auction budget, default the league's
+	Picks  []int   `json:"picks,omitempty"`  // overall pick numbers the team holds, from 1
+	Slot   int     `json:"slot,omitempty"`   // or the team's first-round pick, from which its snake picks follow
+}
+
+// OptimalRoster is the starting lineup with the most projected value a team can buy or draft
+type OptimalRoster struct {
+	Type      string       `json:"type"`      // "auction" or "snake"
+	Projected float64      `json:"projected"` // the starters' total value
+	Budget    float64      `json:"budget,omitempty"`
+	Spent     float64      `json:"spent,omitempty"` // the starters' prices plus the minimum bid for every bench spot
+	Picks     []int        `json:"picks,omitempty"`
+	Lineup    []RosterSpot `json:"lineup"`
+	Open      []string     `json:"open,omitempty"` // starting slots no affordable or available player fills
+	// Optimal is false when the auction search hit its limit; the lineup is the best it found
+	Optimal bool `json:"optimal"`
+}
+
+// RosterSpot is a starter in an optimal roster: at auction with their price, in a snake draft
+// with the pick they're taken at and their ADP
+type RosterSpot struct {
+	Slot       string  `json:"slot"`
+	PlayerID   string  `json:"player_id"`
+	PlayerName string  `json:"player_name"`
+	Position   string  `json:"position"`
+	Team       string  `json:"team"`
+	Value      float64 `json:"value"`
+	Price      float64 `json:"price,omitempty"`
+	Pick       int     `json:"pick,omitempty"`
+	ADP        float64 `json:"adp,omitempty"`
+}
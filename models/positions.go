@@ -0,0 +1,126 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// FieldPositions are the batting positions a player can be eligible at, in display order.
+// LF, CF and RF also make a player eligible in the outfield.
+var FieldPositions = []string{"C", "1B", "2B", "3B", "SS", "LF", "CF", "RF", "OF", "DH"}
+
+// PitcherPositions are the pitching positions a player can be eligible at
+var PitcherPositions = []string{"SP", "RP"}
+
+// benchSlots are reserve roster spots, which don't start
+var benchSlots = []string{"BN", "BENCH", "IL", "NA"}
+
+// EligibilitySettings are the projected games a pitcher needs to be eligible as a starter or
+// reliever, and a batter to keep the positions sources list for them
+type EligibilitySettings struct {
+	MinStarts int `json:"min_starts,omitempty"` // projected starts for SP, default 5
+	MinRelief int `json:"min_relief,omitempty"` // projected relief appearances for RP, default 5
+	MinGames  int `json:"min_games,omitempty"`  // projected games for a batter's listed positions, default none
+}
+
+// ParsePositions reads a source's position list ("RF,DH", "SS/2B", "LF CF") into the positions
+// it makes a player eligible at, in display order. Unknown positions are dropped.
+func ParsePositions(list string) []string {
+	fields := strings.FieldsFunc(strings.ToUpper(list), func(r rune) bool {
+		return r == ',' || r == '/' || r == ' ' || r == ';'
+	})
+	var positions []string
+	for _, field := range fields {
+		positions = append(positions, field)
+		if field == "LF" || field == "CF" || field == "RF" {
+			positions = append(positions, "OF")
+		}
+	}
+	return SortPositions(positions)
+}
+
+// SortPositions dedupes positions and puts them in display order, dropping unknown ones
+func SortPositions(positions []string) []string {
+	var sorted []string
+	for _, position := range slices.Concat(FieldPositions, PitcherPositions) {
+		if slices.Contains(positions, position) {
+			sorted = append(sorted, position)
+		}
+	}
+	return sorted
+}
+
+// BenchSlot reports whether a roster slot is a reserve spot (BN, IL, NA)
+func BenchSlot(slot string) bool {
+	return slices.Contains(benchSlots, strings.ToUpper(strings.TrimSpace(slot)))
+}
+
+// SlotAccepts reports whether a starting roster slot can be filled by a player in the given
+// role ("batter" or "pitcher") who is eligible at positions. CI takes 1B and 3B, MI takes 2B
+// and SS, UTIL takes any batter and P any pitcher. A batter with no known field positions
+// only fills UTIL or DH.
+func SlotAccepts(slot string, role string, positions []string) bool {
+	slot = strings.ToUpper(strings.TrimSpace(slot))
+	if role == "pitcher" {
+		switch slot {
+		case "P":
+			return true
+		case "SP", "RP":
+			return slices.Contains(positions, slot)
+		}
+		return false
+	}
+
+	switch slot {
+	case "P", "SP", "RP":
+		return false
+	case "UTIL", "DH":
+		return true
+	}
+	var batting []string
+	for _, position := range positions {
+		if slices.Contains(FieldPositions, position) {
+			batting = append(batting, position)
+		}
+	}
+	switch slot {
+	case "CI":
+		return slices.Contains(batting, "1B") || slices.Contains(batting, "3B")
+	case "MI":
+		return slices.Contains(batting, "2B") || slices.Contains(batting, "SS")
+	}
+	return slices.Contains(batting, slot)
+}
+
+// TierPositions are the positions players are tiered at. LF, CF and RF are tiered as OF;
+// batters with no known positions are tiered at UTIL and pitchers with neither role at P.
+var TierPositions = []string{"C", "1B", "2B", "3B", "SS", "OF", "DH", "UTIL", "SP", "RP", "P"}
+
+// TiersRequest configures /baseball/tiers
+type TiersRequest struct {
+	ProjectionRequest
+	Tiers *TierSettings `json:"tiers,omitempty"`
+}
+
+// TierSettings are how many tiers players are grouped into at each position
+type TierSettings struct {
+	Count     int            `json:"count,omitempty"`     // tiers per position, default 6
+	Positions map[string]int `json:"positions,omitempty"` // tier counts for particular positions
+	Depth     int            `json:"depth,omitempty"`     // players tiered per position, default 40
+}
+
+// PositionTier is one tier at a position, best tier first
+type PositionTier struct {
+	Position string         `json:"position"`
+	Tier     int            `json:"tier"`
+	High     float64        `json:"high"` // value of the tier's best player
+	Low      float64        `json:"low"`  // value of its worst
+	Players  []TieredPlayer `json:"players"`
+}
+
+// TieredPlayer is a player in a tier
+type TieredPlayer struct {
+	PlayerName string  `json:"player_name"`
+	Team       string  `json:"team"`
+	Value      float64 `json:"value"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePositions(t *testing.T) {
+	tests := map[string][]string{
+		"RF,DH":   {"RF", "OF", "DH"},
+		"SS/2B":   {"2B", "SS"},
+		"lf cf":   {"LF", "CF", "OF"},
+		"DH,SP":   {"DH", "SP"},
+		"XX;1B":   {"1B"},
+		"":        nil,
+		"OF,LF":   {"LF", "OF"},
+		"C, 1B":   {"C", "1B"},
+		"RP;SP":   {"SP", "RP"},
+		"3B,3B":   {"3B"},
+		"UT,DH":   {"DH"},
+		"2B,SS,3": {"2B", "SS"},
+	}
+	for list, want := range tests {
+		if got := ParsePositions(list); !slices.Equal(got, want) {
+			t.Errorf("ParsePositions(%q) = %v, want %v", list, got, want)
+		}
+	}
+}
+
+func TestSlotAccepts(t *testing.T) {
+	tests := []struct {
+		slot      string
+		role      string
+		positions []string
+		want      bool
+	}{
+		{"C", "batter", []string{"C", "1B"}, true},
+		{"1B", "batter", []string{"C", "1B"}, true},
+		{"SS", "batter", []string{"C", "1B"}, false},
+		{"CI", "batter", []string{"3B"}, true},
+		{"MI", "batter", []string{"3B"}, false},
+		{"MI", "batter", []string{"2B"}, true},
+		{"OF", "batter", []string{"CF", "OF"}, true},
+		{"util", "batter", []string{"C"}, true},
+		{"P", "batter", []string{"DH", "SP"}, false},
+		{"UTIL", "batter", nil, true},
+		{"DH", "batter", nil, true},
+		{"C", "batter", nil, false},
+		{"OF", "batter", nil, false},
+		{"CI", "batter", []string{"SP"}, false},
+		{"P", "pitcher", nil, true},
+		{"SP", "pitcher", []string{"SP"}, true},
+		{"RP", "pitcher", []string{"SP"}, false},
+		{"UTIL", "pitcher", []string{"SP"}, false},
+	}
+	for _, tt := range tests {
+		if got := SlotAccepts(tt.slot, tt.role, tt.positions); got != tt.want {
+			t.Errorf("SlotAccepts(%q, %q, %v) = %v, want %v", tt.slot, tt.role, tt.positions, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package models
+
+import "strings"
+
+// ScoringPreset is a host's default points scoring. Hosts revise their defaults now and then,
+// so treat these as a starting point and override what your league changed.
+type ScoringPreset struct {
+	Name        string         `json:"name"`
+	Host        string         `json:"host"`
+	Description string         `json:"description"`
+	Settings    LeagueSettings `json:"settings"`
+}
+
+// Presets is the built-in catalog of standard points leagues
+var Presets = []ScoringPreset{
+	{
+		Name:        "espn",
+		Host:        "ESPN",
+		Description: "ESPN standard head-to-head points",
+		Settings: LeagueSettings{Rules: []ScoringRule{
+			{Position: "batter", Stat: "R", Weight: 1},
+			{Position: "batter", Stat: "TB", Weight: 1},
+			{Position: "batter", Stat: "RBI", Weight: 1},
+			{Position: "batter", Stat: "BB", Weight: 1},
+			{Position: "batter", Stat: "SO", Weight: -1},
+			{Position: "batter", Stat: "SB", Weight: 1},
+			{Position: "pitcher", Stat: "IP", Weight: 3},
+			{Position: "pitcher", Stat: "H", Weight: -1},
+			{Position: "pitcher", Stat: "ER", Weight: -2},
+			{Position: "pitcher", Stat: "BB", Weight: -1},
+			{Position: "pitcher", Stat: "SO", Weight: 1},
+			{Position: "pitcher", Stat: "W", Weight: 2},
+			{Position: "pitcher", Stat: "L", Weight: -2},
+			{Position: "pitcher", Stat: "SV", Weight: 5},
+			{Position: "pitcher", Stat: "HLD", Weight: 2},
+		}},
+	},
+	{
+		Name:        "yahoo",
+		Host:        "Yahoo",
+		Description: "Yahoo standard head-to-head points",
+		Settings: LeagueSettings{Rules: []ScoringRule{
+			{Position: "batter", Stat: "1B", Weight: 2.6},
+			{Position: "batter", Stat: "2B", Weight: 5.2},
+			{Position: "batter", Stat: "3B", Weight: 7.8},
+			{Position: "batter", Stat: "HR", Weight: 10.4},
+			{Position: "batter", Stat: "R", Weight: 1.9},
+			{Position: "batter", Stat: "RBI", Weight: 1.9},
+			{Position: "batter", Stat: "BB", Weight: 2.6},
+			{Position: "batter", Stat: "HBP", Weight: 2.6},
+			{Position: "batter", Stat: "SB", Weight: 4.2},
+			{Position: "pitcher", Stat: "IP", Weight: 7.4},
+			{Position: "pitcher", Stat: "H", Weight: -2.6},
+			{Position: "pitcher", Stat: "ER", Weight: -3},
+			{Position: "pitcher", Stat: "BB", Weight: -2.6},
+			{Position: "pitcher", Stat: "HBP", Weight: -2.6},
+			{Position: "pitcher", Stat: "SO", Weight: 3},
+		}},
+	},
+	{
+		Name:        "cbs",
+		Host:        "CBS",
+		Description: "CBS standard head-to-head points",
+		Settings: LeagueSettings{Rules: []ScoringRule{
+			{Position: "batter", Stat: "1B", Weight: 1},
+			{Position: "batter", Stat: "2B", Weight: 2},
+			{Position: "batter", Stat: "3B", Weight: 3},
+			{Position: "batter", Stat: "HR", Weight: 4},
+			{Position: "batter", Stat: "R", Weight: 1},
+			{Position: "batter", Stat: "RBI", Weight: 1},
+			{Position: "batter", Stat: "BB", Weight: 1},
+			{Position: "batter", Stat: "SO", Weight: -0.5},
+			{Position: "batter", Stat: "SB", Weight: 2},
+			{Position: "batter", Stat: "CS", Weight: -1},
+			{Position: "pitcher", Stat: "IP", Weight: 3},
+			{Position: "pitcher", Stat: "H", Weight: -1},
+			{Position: "pitcher", Stat: "ER", Weight: -1},
+			{Position: "pitcher", Stat: "BB", Weight: -1},
+			{Position: "pitcher", Stat: "SO", Weight: 1},
+			{Position: "pitcher", Stat: "W", Weight: 7},
+			{Position: "pitcher", Stat: "L", Weight: -5},
+			{Position: "pitcher", Stat: "SV", Weight: 7},
+		}},
+	},
+	{
+		Name:        "fantrax",
+		Host:        "Fantrax",
+		Description: "Fantrax default points",
+		Settings: LeagueSettings{Rules: []ScoringRule{
+			{Position: "batter", Stat: "1B", Weight: 1},
+			{Position: "batter", Stat: "2B", Weight: 2},
+			{Position: "batter", Stat: "3B", Weight: 3},
+			{Position: "batter", Stat: "HR", Weight: 4},
+			{Position: "batter", Stat: "R", Weight: 1},
+			{Position: "batter", Stat: "RBI", Weight: 1},
+			{Position: "batter", Stat: "BB", Weight: 1},
+			{Position: "batter", Stat: "HBP", Weight: 1},
+			{Position: "batter", Stat: "SO", Weight: -1},
+			{Position: "batter", Stat: "SB", Weight: 2},
+			{Position: "batter", Stat: "CS", Weight: -1},
+			{Position: "pitcher", Stat: "OUTS", Weight: 1},
+			{Position: "pitcher", Stat: "H", Weight: -1},
+			{Position: "pitcher", Stat: "ER", Weight: -2},
+			{Position: "pitcher", Stat: "BB", Weight: -1},
+			{Position: "pitcher", Stat: "SO", Weight: 1},
+			{Position: "pitcher", Stat: "W", Weight: 5},
+			{Position: "pitcher", Stat: "L", Weight: -5},
+			{Position: "pitcher", Stat: "SV", Weight: 5},
+			{Position: "pitcher", Stat: "HLD", Weight: 3},
+		}},
+	},
+}
+
+// LookupPreset finds a preset by name, ignoring case
+func LookupPreset(name string) (ScoringPreset, bool) {
+	for _, preset := range Presets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, true
+		}
+	}
+	return ScoringPreset{}, false
+}
+
+// Merge lays a league's own settings over the preset. Every rule in overrides (including the
+// fixed weights it sets) replaces the preset's rule for the same position and stat, or is added
+// when the preset doesn't score that stat; a weight of 0 drops a preset rule.
+func (p ScoringPreset) Merge(overrides LeagueSettings) LeagueSettings {
+	rules := append([]ScoringRule(nil), p.Settings.ScoringRules()...)
+	for _, override := range overrides.ScoringRules() {
+		replaced := false
+		for i, rule := range rules {
+			if sameRule(rule, override) {
+				rules[i] = override
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			rules = append(rules, override)
+		}
+	}
+	return LeagueSettings{Rules: rules}
+}
+
+// sameRule reports whether two rules score the same thing: same position and stat, and both
+// per unit or both threshold bonuses
+func sameRule(a, b ScoringRule) bool {
+	return a.Position == b.Position && strings.EqualFold(a.Stat, b.Stat) && (a.Threshold == nil) == (b.Threshold == nil)
+}
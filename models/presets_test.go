@@ -0,0 +1,104 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLookupPreset(t *testing.T) {
+	for _, name := range []string{"espn", "Yahoo", "CBS", "fantrax"} {
+		preset, ok := LookupPreset(name)
+		if !ok {
+			t.Errorf("LookupPreset(%q) found nothing", name)
+			continue
+		}
+		// Every preset scores stats the scorer knows
+		for _, rule := range preset.Settings.Rules {
+			if _, ok := LookupStat(rule.Stat); !ok {
+				t.Errorf("%s scores unknown stat %q", preset.Name, rule.Stat)
+			}
+		}
+	}
+	if _, ok := LookupPreset("nope"); ok {
+		t.Error("LookupPreset found an unknown preset")
+	}
+}
+
+func TestPresetMerge(t *testing.T) {
+	threshold := 30.0
+	preset := ScoringPreset{Name: "test", Settings: LeagueSettings{Rules: []ScoringRule{
+		{Position: "batter", Stat: "HR", Weight: 4},
+		{Position: "batter", Stat: "SB", Weight: 2},
+		{Position: "pitcher", Stat: "SV", Weight: 5},
+	}}}
+
+	tests := []struct {
+		name      string
+		overrides LeagueSettings
+		want      []ScoringRule
+	}{
+		{
+			name:      "no overrides",
+			overrides: LeagueSettings{},
+			want:      preset.Settings.Rules,
+		},
+		{
+			name: "override replaces the same stat, ignoring case, and new stats are added",
+			overrides: LeagueSettings{Rules: []ScoringRule{
+				{Position: "pitcher", Stat: "sv", Weight: 7},
+				{Position: "pitcher", Stat: "HLD", Weight: 3},
+			}},
+			want: []ScoringRule{
+				{Position: "batter", Stat: "HR", Weight: 4},
+				{Position: "batter", Stat: "SB", Weight: 2},
+				{Position: "pitcher", Stat: "sv", Weight: 7},
+				{Position: "pitcher", Stat: "HLD", Weight: 3},
+			},
+		},
+		{
+			name:      "a stat scored for the other position is a different rule",
+			overrides: LeagueSettings{Rules: []ScoringRule{{Position: "pitcher", Stat: "HR", Weight: -2}}},
+			want:      append(slices.Clone(preset.Settings.Rules), ScoringRule{Position: "pitcher", Stat: "HR", Weight: -2}),
+		},
+		{
+			name:      "a threshold bonus adds to the per-unit rule",
+			overrides: LeagueSettings{Rules: []ScoringRule{{Position: "batter", Stat: "HR", Weight: 10, Threshold: &threshold}}},
+			want:      append(slices.Clone(preset.Settings.Rules), ScoringRule{Position: "batter", Stat: "HR", Weight: 10, Threshold: &threshold}),
+		},
+		{
+			name: "fixed weights override too",
+			overrides: func() LeagueSettings {
+				var settings LeagueSettings
+				settings.Batting.StolenBases = 3
+				return settings
+			}(),
+			want: []ScoringRule{
+				{Position: "batter", Stat: "HR", Weight: 4},
+				{Position: "batter", Stat: "SB", Weight: 3},
+				{Position: "pitcher", Stat: "SV", Weight: 5},
+			},
+		},
+		{
+			name:      "a zero weight replaces the preset's rule",
+			overrides: LeagueSettings{Rules: []ScoringRule{{Position: "batter", Stat: "SB", Weight: 0}}},
+			want: []ScoringRule{
+				{Position: "batter", Stat: "HR", Weight: 4},
+				{Position: "batter", Stat: "SB", Weight: 0},
+				{Position: "pitcher", Stat: "SV", Weight: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := preset.Merge(tt.overrides)
+			if !slices.Equal(got.Rules, tt.want) {
+				t.Errorf("Merge = %+v, want %+v", got.Rules, tt.want)
+			}
+		})
+	}
+
+	// Merging leaves the catalog alone
+	if preset.Settings.Rules[2].Weight != 5 {
+		t.Errorf("Merge changed the preset: %+v", preset.Settings.Rules)
+	}
+}
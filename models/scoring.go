@@ -0,0 +1,72 @@
+package models
+
+// ScoringRule scores one stat for batters or pitchers. Stat is a projected stat ("1B", "HLD")
+// or, with a Formula, a name for the value the formula computes ("K9" = "SO * 9 / IP").
+// Without a threshold the rule pays Weight per unit; with one it pays Weight once when the
+// value reaches the threshold (or stays at or under it, for AtMost).
+type ScoringRule struct {
+	Position  string   `json:"position"`            // "batter" or "pitcher"
+	Stat      string   `json:"stat"`                // stat scored, or the name of the formula's value
+	Formula   string   `json:"formula,omitempty"`   // e.g. "1B + 2*2B + 3*3B + 4*HR"
+	Weight    float64  `json:"weight"`              // points per unit, or the bonus with a threshold
+	Threshold *float64 `json:"threshold,omitempty"` // pay Weight once at this value
+	AtMost    bool     `json:"at_most,omitempty"`   // the threshold is a ceiling (ERA <= 3.00)
+	Estimate  bool     `json:"estimate,omitempty"`  // estimate the stat when a source doesn't project it (QS, TB, 1B)
+}
+
+// RuleCoverage is a scoring rule that a source can't score because it doesn't project a stat
+// the rule needs
+type RuleCoverage struct {
+	Source   string `json:"source"`
+	Position string `json:"position"`
+	Stat     string `json:"stat"`
+}
+
+// ScoringRules returns the league's rules: Rules when set, otherwise one rule per nonzero
+// batting and pitching weight. Events that can't be projected (hitting for the cycle,
+// no-hitters, perfect games) have no rule.
+func (s LeagueSettings) ScoringRules() []ScoringRule {
+	if len(s.Rules) > 0 {
+		return s.Rules
+	}
+	var rules []ScoringRule
+	for _, position := range []string{"batter", "pitcher"} {
+		for _, weight := range s.weights(position) {
+			if weight.weight != 0 {
+				rules = append(rules, ScoringRule{Position: position, Stat: string(weight.stat), Weight: weight.weight})
+			}
+		}
+	}
+	return rules
+}
+
+type statWeight struct {
+	stat   Stat
+	weight float64
+}
+
+// weights maps the fixed batting or pitching settings onto the stats they score
+func (s LeagueSettings) weights(position string) []statWeight {
+	if position == "pitcher" {
+		return []statWeight{
+			{StatIP, s.Pitching.InningsPitched},
+			{StatOuts, s.Pitching.Outs},
+			{StatH, s.Pitching.HitsAllowed},
+			{StatER, s.Pitching.EarnedRuns},
+			{StatBB, s.Pitching.WalksIssued},
+			{StatSO, s.Pitching.Strikeouts},
+			{StatW, s.Pitching.Wins},
+			{StatL, s.Pitching.Losses},
+			{StatSV, s.Pitching.Saves},
+			{StatHLD, s.Pitching.Holds},
+		}
+	}
+	return []statWeight{
+		{StatR, s.Batting.RunsScored},
+		{StatTB, s.Batting.TotalBases},
+		{StatRBI, s.Batting.RunsBattedIn},
+		{StatBB, s.Batting.Walks},
+		{StatSO, s.Batting.Strikeouts},
+		{StatSB, s.Batting.StolenBases},
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+// SimulationRequest configures /baseball/simulate
+type SimulationRequest struct {
+	ProjectionRequest
+	Simulation SimulationSettings `json:"simulation"`
+}
+
+// SimulationSettings configure a Monte Carlo run of season outcomes. The same seed and
+// settings always give the same results.
+type SimulationSettings struct {
+	Seasons         int     `json:"seasons,omitempty"`           // seasons simulated per player, default 1000
+	Seed            int64   `json:"seed,omitempty"`              // random seed
+	Workers         int     `json:"workers,omitempty"`           // parallel workers, default one per CPU
+	PlayingTimeRisk float64 `json:"playing_time_risk,omitempty"` // spread of the playing-time multiplier, default 0.15
+	MinSpread       float64 `json:"min_spread,omitempty"`        // least spread of a stat as a share of its projection, default 0.1
+}
+
+// PlayerOutcome is the distribution of a player's simulated fantasy points
+type PlayerOutcome struct {
+	PlayerName string  `json:"player_name"`
+	Position   string  `json:"position"`
+	Team       string  `json:"team"`
+	Projected  float64 `json:"projected"` // points of the combined projection
+	Mean       float64 `json:"mean"`
+	P10        float64 `json:"p10"`
+	P50        float64 `json:"p50"`
+	P90        float64 `json:"p90"`
+}
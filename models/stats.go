@@ -0,0 +1,77 @@
+package models
+
+import "strings"
+
+// Stat names a projected statistic, using the abbreviations projection sites print in their
+// headers. Batting and pitching lines share names where the stat is the same event seen from
+// the other side (a pitcher's "H" is hits allowed, "SO" strikeouts recorded).
+type Stat string
+
+const (
+	StatG    Stat = "G"    // games
+	StatPA   Stat = "PA"   // plate appearances
+	StatAB   Stat = "AB"   // at bats
+	StatH    Stat = "H"    // hits (allowed, for pitchers)
+	Stat1B   Stat = "1B"   // singles
+	Stat2B   Stat = "2B"   // doubles
+	Stat3B   Stat = "3B"   // triples
+	StatHR   Stat = "HR"   // home runs (allowed, for pitchers)
+	StatTB   Stat = "TB"   // total bases: 1B + 2*2B + 3*3B + 4*HR
+	StatR    Stat = "R"    // runs (allowed, for pitchers)
+	StatRBI  Stat = "RBI"  // runs batted in
+	StatBB   Stat = "BB"   // walks (issued, for pitchers)
+	StatIBB  Stat = "IBB"  // intentional walks
+	StatSO   Stat = "SO"   // strikeouts
+	StatHBP  Stat = "HBP"  // hit by pitch
+	StatSF   Stat = "SF"   // sacrifice flies
+	StatSH   Stat = "SH"   // sacrifice hits
+	StatSB   Stat = "SB"   // stolen bases
+	StatCS   Stat = "CS"   // caught stealing
+	StatGIDP Stat = "GIDP" // grounded into double plays
+	StatAVG  Stat = "AVG"  // batting average
+	StatOBP  Stat = "OBP"  // on-base percentage
+	StatSLG  Stat = "SLG"  // slugging percentage
+	StatOPS  Stat = "OPS"  // on-base plus slugging
+	StatWOBA Stat = "wOBA" // weighted on-base average
+	StatWAR  Stat = "WAR"  // wins above replacement
+
+	StatW    Stat = "W"    // wins
+	StatL    Stat = "L"    // losses
+	StatGS   Stat = "GS"   // games started
+	StatSV   Stat = "SV"   // saves
+	StatHLD  Stat = "HLD"  // holds
+	StatBS   Stat = "BS"   // blown saves
+	StatCG   Stat = "CG"   // complete games
+	StatQS   Stat = "QS"   // quality starts
+	StatIP   Stat = "IP"   // innings pitched, as true innings
+	StatOuts Stat = "OUTS" // outs recorded (IP * 3)
+	StatTBF  Stat = "TBF"  // total batters faced
+	StatER   Stat = "ER"   // earned runs
+	StatERA  Stat = "ERA"  // earned run average
+	StatWHIP Stat = "WHIP" // walks plus hits per inning
+)
+
+// BattingStats lists every stat a batting line can carry
+var BattingStats = []Stat{
+	StatG, StatPA, StatAB, StatH, Stat1B, Stat2B, Stat3B, StatHR, StatTB, StatR, StatRBI, StatBB,
+	StatIBB, StatSO, StatHBP, StatSF, StatSH, StatSB, StatCS, StatGIDP, StatAVG, StatOBP, StatSLG, StatOPS,
+	StatWOBA, StatWAR,
+}
+
+// PitchingStats lists every stat a pitching line can carry
+var PitchingStats = []Stat{
+	StatG, StatGS, StatW, StatL, StatSV, StatHLD, StatBS, StatCG, StatIP, StatOuts, StatTBF, StatH,
+	StatR, StatER, StatHR, StatBB, StatIBB, StatHBP, StatSO, StatERA, StatWHIP, StatQS, StatWAR,
+}
+
+// LookupStat finds the stat a column name refers to, ignoring case ("woba" is wOBA)
+func LookupStat(name string) (Stat, bool) {
+	for _, catalog := range [][]Stat{BattingStats, PitchingStats} {
+		for _, stat := range catalog {
+			if strings.EqualFold(string(stat), name) {
+				return stat, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,74 @@
+package models
+
+import "strings"
+
+// Team is a canonical MLB club. Codes follow MLB's own abbreviations, which FantasyPros uses;
+// Aliases holds the codes other sources use for the same club (FanGraphs writes KCR, SDP, ...).
+type Team struct {
+	Code     string   `json:"code"`
+	Name     string   `json:"name"`
+	League   string   `json:"league"`
+	Division string   `json:"division"`
+	Aliases  []string `json:"aliases"`
+}
+
+// Teams is the canonical MLB team table
+var Teams = []Team{
+	{Code: "ARI", Name: "Arizona Diamondbacks", League: "NL", Division: "West", Aliases: []string{"AZ"}},
+	{Code: "ATH", Name: "Athletics", League: "AL", Division: "West", Aliases: []string{"OAK"}},
+	{Code: "ATL", Name: "Atlanta Braves", League: "NL", Division: "East"},
+	{Code: "BAL", Name: "Baltimore Orioles", League: "AL", Division: "East"},
+	{Code: "BOS", Name: "Boston Red Sox", League: "AL", Division: "East"},
+	{Code: "CHC", Name: "Chicago Cubs", League: "NL", Division: "Central", Aliases: []string{"CHN"}},
+	{Code: "CWS", Name: "Chicago White Sox", League: "AL", Division: "Central", Aliases: []string{"CHW", "CHA"}},
+	{Code: "CIN", Name: "Cincinnati Reds", League: "NL", Division: "Central"},
+	{Code: "CLE", Name: "Cleveland Guardians", League: "AL", Division: "Central"},
+	{Code: "COL", Name: "Colorado Rockies", League: "NL", Division: "West"},
+	{Code: "DET", Name: "Detroit Tigers", League: "AL", Division: "Central"},
+	{Code: "HOU", Name: "Houston Astros", League: "AL", Division: "West"},
+	{Code: "KC", Name: "Kansas City Royals", League: "AL", Division: "Central", Aliases: []string{"KCR", "KCA"}},
+	{Code: "LAA", Name: "Los Angeles Angels", League: "AL", Division: "West", Aliases: []string{"ANA"}},
+	{Code: "LAD", Name: "Los Angeles Dodgers", League: "NL", Division: "West", Aliases: []string{"LA", "LAN"}},
+	{Code: "MIA", Name: "Miami Marlins", League: "NL", Division: "East", Aliases: []string{"FLA"}},
+	{Code: "MIL", Name: "Milwaukee Brewers", League: "NL", Division: "Central"},
+	{Code: "MIN", Name: "Minnesota Twins", League: "AL", Division: "Central"},
+	{Code: "NYM", Name: "New York Mets", League: "NL", Division: "East", Aliases: []string{"NYN"}},
+	{Code: "NYY", Name: "New York Yankees", League: "AL", Division: "East", Aliases: []string{"NYA"}},
+	{Code: "PHI", Name: "Philadelphia Phillies", League: "NL", Division: "East"},
+	{Code: "PIT", Name: "Pittsburgh Pirates", League: "NL", Division: "Central"},
+	{Code: "SD", Name: "San Diego Padres", League: "NL", Division: "West", Aliases: []string{"SDP", "SDN"}},
+	{Code: "SEA", Name: "Seattle Mariners", League: "AL", Division: "West"},
+	{Code: "SF", Name: "San Francisco Giants", League: "NL", Division: "West", Aliases: []string{"SFG", "SFN"}},
+	{Code: "STL", Name: "St. Louis Cardinals", League: "NL", Division: "Central", Aliases: []string{"SLN"}},
+	{Code: "TB", Name: "Tampa Bay Rays", League: "AL", Division: "East", Aliases: []string{"TBR", "TBA"}},
+	{Code: "TEX", Name: "Texas Rangers", League: "AL", Division: "West"},
+	{Code: "TOR", Name: "Toronto Blue Jays", League: "AL", Division: "East"},
+	{Code: "WSH", Name: "Washington Nationals", League: "NL", Division: "East", Aliases: []string{"WSN", "WAS"}},
+}
+
+// teamsByCode indexes Teams by canonical code and every alias
+var teamsByCode = func() map[string]Team {
+	index := make(map[string]Team)
+	for _, team := range Teams {
+		index[team.Code] = team
+		for _, alias := range team.Aliases {
+			index[alias] = team
+		}
+	}
+	return index
+}()
+
+// LookupTeam finds the canonical team for any source's team code
+func LookupTeam(code string) (Team, bool) {
+	team, ok := teamsByCode[strings.ToUpper(strings.TrimSpace(code))]
+	return team, ok
+}
+
+// CanonicalTeam maps a source's team code onto the canonical code ("KCR" -> "KC"). Codes that
+// aren't in the table (blank for free agents, "FA", ...) are returned trimmed and uppercased.
+func CanonicalTeam(code string) string {
+	if team, ok := LookupTeam(code); ok {
+		return team.Code
+	}
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,50 @@
+package models
+
+import "testing"
+
+func TestCanonicalTeam(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"KC", "KC"},
+		{"KCR", "KC"},
+		{"sdp", "SD"},
+		{" SFG ", "SF"},
+		{"WSN", "WSH"},
+		{"CHW", "CWS"},
+		{"OAK", "ATH"},
+		{"AZ", "ARI"},
+		{"TBR", "TB"},
+		{"", ""},
+		{"fa", "FA"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalTeam(tt.code); got != tt.want {
+			t.Errorf("CanonicalTeam(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+
+	if team, ok := LookupTeam("NYA"); !ok || team.Name != "New York Yankees" {
+		t.Errorf("LookupTeam(NYA) = %+v, %v", team, ok)
+	}
+	if _, ok := LookupTeam("FA"); ok {
+		t.Error("LookupTeam(FA) found a team")
+	}
+}
+
+func TestTeamsTable(t *testing.T) {
+	if len(Teams) != 30 {
+		t.Errorf("%d teams, want 30", len(Teams))
+	}
+	// Every code and alias names exactly one club
+	seen := make(map[string]string)
+	for _, team := range Teams {
+		for _, code := range append([]string{team.Code}, team.Aliases...) {
+			if other, ok := seen[code]; ok {
+				t.Errorf("%s names both %s and %s", code, other, team.Code)
+			}
+			seen[code] = team.Code
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ColumnMap records which CSV column feeds each `csv`-tagged field of a struct.
+//
+// Fields declare the headers they accept as a "|" separated alias list, optionally
+// followed by ",optional" when the column may be absent from an export:
+//
+//	Strikeouts float64 `csv:"SO|K"`
+//	Rank       int     `csv:"#|Rank,optional"`
+type ColumnMap struct {
+	typ    reflect.Type
+	fields []mappedField
+	absent []string // primary names of optional columns the header doesn't have
+}
+
+type mappedField struct {
+	field  int    // index of the struct field
+	column int    // index of the CSV column
+	header string // header as it appears in the file
+	name   string // the field's primary column name (first alias)
+}
+
+// MissingColumnsError lists the required columns that were not found in a header row
+type MissingColumnsError struct {
+	Missing []string
+}
+
+func (e *MissingColumnsError) Error() string {
+	return "missing required columns: " + strings.Join(e.Missing, ", ")
+}
+
+// MapColumns matches a CSV header row against the csv tags declared on target's struct type
+func MapColumns(header []string, target interface{}) (*ColumnMap, error) {
+	t := reflect.TypeOf(target)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot map columns onto %s", t)
+	}
+
+	// First occurrence wins when a file repeats a header
+	positions := make(map[string]int, len(header))
+	for i, h := range header {
+		key := headerKey(h)
+		if _, seen := positions[key]; !seen {
+			positions[key] = i
+		}
+	}
+
+	m := &ColumnMap{typ: t}
+	var missing []string
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("csv")
+		if !ok || tag == "-" {
+			continue
+		}
+		aliases, optional := parseColumnTag(tag)
+
+		column := -1
+		for _, alias := range aliases {
+			if p, ok := positions[headerKey(alias)]; ok {
+				column = p
+				break
+			}
+		}
+		if column < 0 {
+			if optional {
+				m.absent = append(m.absent, aliases[0])
+			} else {
+				missing = append(missing, strings.Join(aliases, "/"))
+			}
+			continue
+		}
+		m.fields = append(m.fields, mappedField{field: i, column: column, header: header[column], name: aliases[0]})
+	}
+
+	if len(missing) > 0 {
+		return nil, &MissingColumnsError{Missing: missing}
+	}
+	return m, nil
+}
+
+// Absent lists the optional columns (by primary name) that the header didn't have
+func (m *ColumnMap) Absent() []string {
+	return m.absent
+}
+
+// CellParser is implemented by field types that decode a cell themselves (see Outs)
+type CellParser interface {
+	ParseCell(raw string) error
+}
+
+// CellError describes a cell that was blank or could not be parsed as its field's type
+type CellError struct {
+	Column string // header as it appears in the file
+	Name   string // the field's primary column name
+	Value  string
+	Blank  bool
+}
+
+// Decode fills the mapped fields of dest (a pointer to the mapped struct type) from a CSV record.
+// Unparseable numbers still decode leniently (as ParseFloat/ParseInt would), but every blank or
+// malformed cell is returned so callers can report it.
+func (m *ColumnMap) Decode(record []string, dest interface{}) []CellError {
+	var problems []CellError
+	v := reflect.ValueOf(dest).Elem()
+	for _, f := range m.fields {
+		raw := ""
+		if f.column < len(record) {
+			raw = strings.TrimSpace(record[f.column])
+		}
+		if raw == "" {
+			problems = append(problems, CellError{Column: f.header, Name: f.name, Blank: true})
+		}
+
+		field := v.Field(f.field)
+		var err error
+		if parser, ok := field.Addr().Interface().(CellParser); ok {
+			if raw != "" {
+				err = parser.ParseCell(raw)
+			}
+		} else {
+			switch field.Kind() {
+			case reflect.String:
+				field.SetString(raw)
+			case reflect.Int, reflect.Int32, reflect.Int64:
+				field.SetInt(int64(ParseInt(raw)))
+				if raw != "" {
+					_, err = strconv.Atoi(raw)
+				}
+			case reflect.Float32, reflect.Float64:
+				field.SetFloat(ParseFloat(raw))
+				if raw != "" {
+					_, err = strconv.ParseFloat(raw, 64)
+				}
+			}
+		}
+		if err != nil {
+			problems = append(problems, CellError{Column: f.header, Name: f.name, Value: raw})
+		}
+	}
+	return problems
+}
+
+// parseColumnTag splits a csv tag into its header aliases and the optional flag
+func parseColumnTag(tag string) ([]string, bool) {
+	names, opts, _ := strings.Cut(tag, ",")
+	return strings.Split(names, "|"), opts == "optional"
+}
+
+// headerKey normalizes a header cell for matching (case, whitespace and byte-order mark)
+func headerKey(h string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// columnsRow declares columns the way source rows do: aliases, an optional column, and a
+// field without a tag
+type columnsRow struct {
+	Name       string  `csv:"Name|Player"`
+	Team       string  `csv:"Team,optional"`
+	Strikeouts float64 `csv:"SO|K"`
+	Rank       int     `csv:"#|Rank,optional"`
+	Ignored    string
+	Skipped    string `csv:"-"`
+}
+
+func TestMapColumns(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		record []string
+		want   columnsRow
+	}{
+		{
+			name:   "primary names",
+			header: []string{"Name", "Team", "SO", "#"},
+			record: []string{"Aaron Judge", "NYY", "160", "1"},
+			want:   columnsRow{Name: "Aaron Judge", Team: "NYY", Strikeouts: 160, Rank: 1},
+		},
+		{
+			name:   "aliases in any order",
+			header: []string{"Rank", "K", "Player"},
+			record: []string{"2", "171", "Shohei Ohtani"},
+			want:   columnsRow{Name: "Shohei Ohtani", Strikeouts: 171, Rank: 2},
+		},
+		{
+			name:   "optional columns absent",
+			header: []string{"Player", "SO"},
+			record: []string{"Juan Soto", "130"},
+			want:   columnsRow{Name: "Juan Soto", Strikeouts: 130},
+		},
+		{
+			name:   "case, whitespace and byte-order mark",
+			header: []string{"\ufeffNAME", " team ", "so"},
+			record: []string{"Bobby Witt Jr.", "KC", "110"},
+			want:   columnsRow{Name: "Bobby Witt Jr.", Team: "KC", Strikeouts: 110},
+		},
+		{
+			name:   "first of repeated headers wins",
+			header: []string{"Name", "SO", "so", "Name"},
+			record: []string{"Gunnar Henderson", "150", "999", "Someone Else"},
+			want:   columnsRow{Name: "Gunnar Henderson", Strikeouts: 150},
+		},
+		{
+			name:   "an earlier alias beats a later one",
+			header: []string{"Player", "Name", "SO"},
+			record: []string{"Full Name", "Short", "100"},
+			want:   columnsRow{Name: "Short", Strikeouts: 100},
+		},
+		{
+			name:   "short record",
+			header: []string{"Name", "SO", "Team"},
+			record: []string{"Corbin Carroll", " 140 "},
+			want:   columnsRow{Name: "Corbin Carroll", Strikeouts: 140},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := MapColumns(tt.header, &columnsRow{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got columnsRow
+			columns.Decode(tt.record, &got)
+			if got != tt.want {
+				t.Errorf("Decode = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapColumnsMissing(t *testing.T) {
+	_, err := MapColumns([]string{"Team", "Rank", "HR"}, columnsRow{})
+	var missing *MissingColumnsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("err = %v, want a MissingColumnsError", err)
+	}
+	// Required columns are listed with every alias, optional ones not at all
+	if want := []string{"Name/Player", "SO/K"}; !slices.Equal(missing.Missing, want) {
+		t.Errorf("Missing = %v, want %v", missing.Missing, want)
+	}
+	if want := "missing required columns: Name/Player, SO/K"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	if _, err := MapColumns([]string{"Name"}, "not a struct"); err == nil {
+		t.Error("mapping onto a string succeeded, want an error")
+	}
+}
+
+func TestDecodeCellErrors(t *testing.T) {
+	columns, err := MapColumns([]string{"Name", "Team", "SO", "#"}, columnsRow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var row columnsRow
+	cells := columns.Decode([]string{"Aaron Judge", " ", "1,60", "12x"}, &row)
+
+	// Malformed numbers decode leniently but are reported, as are blank cells
+	if row.Strikeouts != 1 || row.Rank != 12 {
+		t.Errorf("decoded %+v, want the leading numbers of malformed cells", row)
+	}
+	type problem struct {
+		column, value string
+		blank         bool
+	}
+	var got []problem
+	for _, cell := range cells {
+		got = append(got, problem{cell.Column, cell.Value, cell.Blank})
+	}
+	want := []problem{{"Team", "", true}, {"SO", "1,60", false}, {"#", "12x", false}}
+	if !slices.Equal(got, want) {
+		t.Errorf("cell errors = %+v, want %+v", got, want)
+	}
+
+	if cells := columns.Decode([]string{"Aaron Judge", "NYY", "160", "1"}, &row); len(cells) != 0 {
+		t.Errorf("a clean row reported %+v", cells)
+	}
+	// A record too short for a column leaves it blank
+	if cells := columns.Decode([]string{"Aaron Judge", "NYY", "160"}, &row); len(cells) != 1 || !cells[0].Blank || cells[0].Column != "#" {
+		t.Errorf("short record reported %+v, want # blank", cells)
+	}
+}
+
+func TestColumnMapAbsent(t *testing.T) {
+	columns, err := MapColumns([]string{"Player", "K"}, columnsRow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Optional columns are listed by their primary name
+	if want := []string{"Team", "#"}; !slices.Equal(columns.Absent(), want) {
+		t.Errorf("Absent = %v, want %v", columns.Absent(), want)
+	}
+	var row columnsRow
+	cells := columns.Decode([]string{"Aaron Judge", ""}, &row)
+	if len(cells) != 1 || cells[0].Column != "K" || cells[0].Name != "SO" || !cells[0].Blank {
+		t.Errorf("cell errors = %+v, want K blank, named SO", cells)
+	}
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Outs counts the outs a pitcher records. Box scores and FanGraphs exports write innings in
+// baseball notation, where the digit after the point is outs rather than tenths: "173.1" is
+// 173⅓ innings, i.e. 520 outs.
+type Outs float64
+
+// Innings converts outs to true (fractional) innings
+func (o Outs) Innings() float64 {
+	return float64(o) / 3
+}
+
+// ParseCell lets ColumnMap decode an innings column written in baseball notation.
+// Values that aren't valid notation still decode as decimal innings but report an error.
+func (o *Outs) ParseCell(raw string) error {
+	outs, err := ParseOuts(raw)
+	if err != nil {
+		*o = OutsFromInnings(ParseFloat(raw))
+		return err
+	}
+	*o = outs
+	return nil
+}
+
+// ParseOuts parses innings in baseball notation ("199.2" = 199⅔ innings = 599 outs)
+func ParseOuts(s string) (Outs, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	whole, fraction, _ := strings.Cut(s, ".")
+	innings, err := strconv.Atoi(whole)
+	if whole == "" {
+		innings, err = 0, nil
+	}
+	if err != nil || innings < 0 {
+		return 0, fmt.Errorf("invalid innings %q", s)
+	}
+
+	var outs int
+	switch fraction {
+	case "", "0":
+	case "1", "2":
+		outs, _ = strconv.Atoi(fraction)
+	default:
+		return 0, fmt.Errorf("invalid innings %q: the digit after the point counts outs (0-2)", s)
+	}
+	return Outs(innings*3 + outs), nil
+}
+
+// OutsFromInnings converts true decimal innings ("190.5" = 190½) to the nearest whole out
+func OutsFromInnings(innings float64) Outs {
+	return Outs(math.Round(innings * 3))
+}
+
+// OutsFromNotation converts a number that was stored straight from baseball notation
+// (199.2 meaning 199⅔ innings) into outs
+func OutsFromNotation(ip float64) Outs {
+	whole := math.Floor(ip)
+	tenths := math.Round((ip - whole) * 10)
+	if tenths > 2 {
+		// Not valid notation, so it must already be decimal innings
+		return OutsFromInnings(ip)
+	}
+	return Outs(whole*3 + tenths)
+}
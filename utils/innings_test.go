@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseOuts(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Outs
+		wantErr bool
+	}{
+		{raw: "173.1", want: 520},
+		{raw: "199.2", want: 599},
+		{raw: "200.0", want: 600},
+		{raw: "200", want: 600},
+		{raw: " 65.2 ", want: 197},
+		{raw: ".2", want: 2},
+		{raw: "", want: 0},
+		{raw: "190.5", wantErr: true},
+		{raw: "-3.1", wantErr: true},
+		{raw: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseOuts(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ParseOuts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutsParseCell(t *testing.T) {
+	var outs Outs
+	if err := outs.ParseCell("173.1"); err != nil || outs != 520 {
+		t.Errorf("ParseCell(173.1) = %v, %v; want 520 outs", outs, err)
+	}
+	// Decimal innings still decode, but are reported
+	if err := outs.ParseCell("190.5"); err == nil || outs != 572 {
+		t.Errorf("ParseCell(190.5) = %v, %v; want 572 outs and an error", outs, err)
+	}
+	if math.Abs(Outs(520).Innings()-(173+1.0/3)) > 1e-9 {
+		t.Errorf("Innings = %v, want 173⅓", Outs(520).Innings())
+	}
+}
+
+func TestOutsConversions(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Outs
+		want Outs
+	}{
+		{name: "decimal innings", got: OutsFromInnings(173 + 1.0/3), want: 520},
+		{name: "half innings round to the nearest out", got: OutsFromInnings(190.5), want: 572},
+		{name: "notation", got: OutsFromNotation(173.1), want: 520},
+		{name: "notation with two outs", got: OutsFromNotation(199.2), want: 599},
+		{name: "notation that can't be is decimal innings", got: OutsFromNotation(190.5), want: 572},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v outs, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -75,3 +75,22 @@ func NormalizeName(name string) string {
 	normalized, _, _ := transform.String(t, name)
 	return normalized
 }
+
+// nameSuffixes are generational suffixes that sources include inconsistently ("Bobby Witt Jr.")
+var nameSuffixes = map[string]bool{"jr": true, "sr": true, "ii": true, "iii": true, "iv": true}
+
+// NameKey reduces a player name to the form used for identity matching: normalized,
+// lowercased, without punctuation or generational suffixes ("Bobby Witt Jr." -> "bobby witt")
+func NameKey(name string) string {
+	name = strings.ToLower(NormalizeName(name))
+	name = strings.NewReplacer("-", " ", "'", "", "’", "", ",", " ").Replace(name)
+
+	var words []string
+	for i, word := range strings.Fields(name) {
+		if i > 0 && nameSuffixes[word] {
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}